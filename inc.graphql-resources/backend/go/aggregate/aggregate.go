@@ -0,0 +1,185 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/constant"
+	"graphqlapplication/database"
+	"graphqlapplication/input"
+	"graphqlapplication/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregate functions supported by the AggregateFunction enum.
+const (
+	FnCount = "COUNT"
+	FnSum   = "SUM"
+	FnAvg   = "AVG"
+	FnMin   = "MIN"
+	FnMax   = "MAX"
+)
+
+// MetricInput corresponds to the GraphQL MetricInput type.
+// Field may be omitted for COUNT, which then counts the rows.
+type MetricInput struct {
+	Fn    string
+	Field *string
+}
+
+// Args holds the arguments of the {entity}Aggregate queries.
+type Args struct {
+	Filter  *[]*input.FilterInput
+	GroupBy *[]*string
+	Metrics *[]*MetricInput
+}
+
+// metric is a validated metric and the SQL expression that computes it.
+type metric struct {
+	fn    string
+	field *string
+	expr  string
+}
+
+// Query computes the metrics of the rows of a table that match the filters, grouped by the groupBy fields.
+// Fields are resolved through columns; SUM and AVG are only allowed on the fields of numericColumns.
+// Without metrics, the rows are counted. Without groupBy, a single bucket is returned.
+func Query(ctx context.Context, db database.Executor, tableName string, args Args, columns input.ColumnMap, numericColumns input.ColumnMap, dialect database.Dialect) ([]*BucketResolver, error) {
+	whereSQL, _, params, err := input.BuildQuery(ctx, args.Filter, nil, columns, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group by
+	var groupFields []string
+	var groupColumns []string
+	if args.GroupBy != nil {
+		for _, field := range *args.GroupBy {
+			if field == nil || *field == "" {
+				continue
+			}
+			column, ok := columns.Column(*field)
+			if !ok {
+				return nil, apperror.Validation(util.T(ctx, "invalid_group_field", *field))
+			}
+			groupFields = append(groupFields, *field)
+			groupColumns = append(groupColumns, dialect.QuoteIdentifier(column))
+		}
+	}
+
+	// Metrics
+	metrics, err := buildMetrics(ctx, args.Metrics, columns, numericColumns, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	selects := append([]string{}, groupColumns...)
+	for _, m := range metrics {
+		selects = append(selects, m.expr)
+	}
+	sqlQuery := fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(selects, ", "), tableName, whereSQL)
+	if len(groupColumns) > 0 {
+		groupSQL := strings.Join(groupColumns, ", ")
+		sqlQuery += fmt.Sprintf(" GROUP BY %s ORDER BY %s", groupSQL, groupSQL)
+	}
+
+	rows, err := db.QueryContext(ctx, sqlQuery, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []*BucketResolver{}
+	for rows.Next() {
+		values := make([]interface{}, len(selects))
+		dest := make([]interface{}, len(selects))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		bucket := &BucketResolver{}
+		for i, field := range groupFields {
+			bucket.group = append(bucket.group, &GroupResolver{field: field, value: input.NewAny(normalize(values[i]))})
+		}
+		for i, m := range metrics {
+			value := normalize(values[len(groupFields)+i])
+			if m.fn != FnMin && m.fn != FnMax {
+				value = toNumber(value)
+			}
+			bucket.metrics = append(bucket.metrics, &MetricResolver{fn: m.fn, field: m.field, value: input.NewAny(value)})
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// buildMetrics validates the requested metrics and builds their SQL expressions.
+// Without metrics, including when every metric is null, the rows are counted.
+func buildMetrics(ctx context.Context, inputs *[]*MetricInput, columns input.ColumnMap, numericColumns input.ColumnMap, dialect database.Dialect) ([]metric, error) {
+	var requested []*MetricInput
+	if inputs != nil {
+		requested = *inputs
+	}
+	var metrics []metric
+	for _, in := range requested {
+		if in == nil {
+			continue
+		}
+		fn := strings.ToUpper(in.Fn)
+		switch fn {
+		case FnCount, FnSum, FnAvg, FnMin, FnMax:
+		default:
+			return nil, apperror.Validation(util.T(ctx, "invalid_aggregate_function", in.Fn))
+		}
+
+		if in.Field == nil || *in.Field == "" {
+			if fn != FnCount {
+				return nil, apperror.Validation(util.T(ctx, "metric_field_required", fn))
+			}
+			metrics = append(metrics, metric{fn: fn, expr: "COUNT(*)"})
+			continue
+		}
+
+		column, ok := columns.Column(*in.Field)
+		if !ok {
+			return nil, apperror.Validation(util.T(ctx, "invalid_metric_field", *in.Field))
+		}
+		if fn == FnSum || fn == FnAvg {
+			if _, numeric := numericColumns.Column(*in.Field); !numeric {
+				return nil, apperror.Validation(util.T(ctx, "metric_requires_numeric_field", fn, *in.Field))
+			}
+		}
+		metrics = append(metrics, metric{fn: fn, field: in.Field, expr: fmt.Sprintf("%s(%s)", fn, dialect.QuoteIdentifier(column))})
+	}
+	if len(metrics) == 0 {
+		metrics = append(metrics, metric{fn: FnCount, expr: "COUNT(*)"})
+	}
+	return metrics, nil
+}
+
+// normalize converts the values returned by the drivers into values that can be sent to the client.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(constant.DateTimeFormat)
+	}
+	return value
+}
+
+// toNumber converts the result of COUNT, SUM and AVG into a number.
+// Some drivers return DECIMAL results as text.
+func toNumber(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package aggregate
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/input"
+	"reflect"
+	"testing"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	columns := input.ColumnMap{"name": "name", "price": "price"}
+	numericColumns := input.ColumnMap{"price": "price"}
+	field := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		inputs  *[]*MetricInput
+		exprs   []string
+		wantErr bool
+	}{
+		{"no metrics count the rows", nil, []string{"COUNT(*)"}, false},
+		{"empty metrics count the rows", &[]*MetricInput{}, []string{"COUNT(*)"}, false},
+		{"null metrics count the rows", &[]*MetricInput{nil}, []string{"COUNT(*)"}, false},
+		{"count without a field", &[]*MetricInput{{Fn: "count"}}, []string{"COUNT(*)"}, false},
+		{"count of a field", &[]*MetricInput{{Fn: FnCount, Field: field("name")}}, []string{"COUNT(`name`)"}, false},
+		{"sum and average of a numeric field", &[]*MetricInput{{Fn: FnSum, Field: field("price")}, nil, {Fn: FnAvg, Field: field("price")}}, []string{"SUM(`price`)", "AVG(`price`)"}, false},
+		{"minimum and maximum of any field", &[]*MetricInput{{Fn: FnMin, Field: field("name")}, {Fn: FnMax, Field: field("name")}}, []string{"MIN(`name`)", "MAX(`name`)"}, false},
+		{"sum of a field that is not numeric", &[]*MetricInput{{Fn: FnSum, Field: field("name")}}, nil, true},
+		{"average of a field that is not numeric", &[]*MetricInput{{Fn: FnAvg, Field: field("name")}}, nil, true},
+		{"field that is not allowed", &[]*MetricInput{{Fn: FnMax, Field: field("password")}}, nil, true},
+		{"SQL in a field", &[]*MetricInput{{Fn: FnMax, Field: field("price) FROM admin --")}}, nil, true},
+		{"sum without a field", &[]*MetricInput{{Fn: FnSum}}, nil, true},
+		{"unknown function", &[]*MetricInput{{Fn: "MEDIAN", Field: field("price")}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := buildMetrics(context.Background(), tt.inputs, columns, numericColumns, database.MySQL{})
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("buildMetrics() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildMetrics() failed: %v", err)
+			}
+			var exprs []string
+			for _, m := range metrics {
+				exprs = append(exprs, m.expr)
+			}
+			if !reflect.DeepEqual(exprs, tt.exprs) {
+				t.Errorf("buildMetrics() = %v, want %v", exprs, tt.exprs)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package aggregate
+
+import (
+	"graphqlapplication/input"
+)
+
+// BucketResolver contains the metrics of one group of rows.
+type BucketResolver struct {
+	group   []*GroupResolver
+	metrics []*MetricResolver
+}
+
+// GroupResolver contains the value of a groupBy field shared by the rows of a bucket.
+type GroupResolver struct {
+	field string
+	value input.Any
+}
+
+// MetricResolver contains the result of a metric for the rows of a bucket.
+type MetricResolver struct {
+	fn    string
+	field *string
+	value input.Any
+}
+
+func (r *BucketResolver) Group() []*GroupResolver    { return r.group }
+func (r *BucketResolver) Metrics() []*MetricResolver { return r.metrics }
+
+func (r *GroupResolver) Field() string     { return r.field }
+func (r *GroupResolver) Value() *input.Any { return &r.value }
+
+func (r *MetricResolver) Fn() string        { return r.fn }
+func (r *MetricResolver) Field() *string    { return r.field }
+func (r *MetricResolver) Value() *input.Any { return &r.value }
@@ -0,0 +1,124 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"graphqlapplication/constant"
+	"graphqlapplication/util"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// Codes rendered as extensions.code of the GraphQL errors.
+const (
+	CodeNotFound   = "NOT_FOUND"
+	CodeValidation = "VALIDATION"
+	CodeForbidden  = "FORBIDDEN"
+	CodeConflict   = "CONFLICT"
+	CodeInternal   = "INTERNAL"
+)
+
+// CorrelationHeader is the header that carries the correlation id of a request.
+const CorrelationHeader = "X-Correlation-Id"
+
+// Error is an error whose message is safe to show to clients.
+// graphql-go renders its code and extensions in the extensions of the GraphQL error.
+type Error struct {
+	code       string
+	message    string
+	extensions map[string]interface{}
+	cause      error
+}
+
+// New creates an error with a code and a message that is shown to the client.
+func New(code string, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+// NotFound creates the error returned when a record does not exist.
+func NotFound(message string) *Error { return New(CodeNotFound, message) }
+
+// Validation creates the error returned when the arguments of a request are not valid.
+func Validation(message string) *Error { return New(CodeValidation, message) }
+
+// Forbidden creates the error returned when the admin may not perform an action.
+func Forbidden(message string) *Error { return New(CodeForbidden, message) }
+
+// Conflict creates the error returned when a record was changed by another request.
+func Conflict(message string) *Error { return New(CodeConflict, message) }
+
+// Internal creates the error returned when a request fails for a reason the client cannot fix,
+// such as a database error. The details of err are only logged, with the correlation id of the request;
+// the client gets the message of key, followed by the correlation id as its last argument.
+func Internal(ctx context.Context, err error, key string, args ...interface{}) *Error {
+	id := CorrelationID(ctx)
+	log.Printf("[%s] %s %v: %v", id, key, args, err)
+	messageArgs := append(append([]interface{}{}, args...), util.T(ctx, "internal_error_reference", id))
+	message := util.T(ctx, key, messageArgs...)
+	return &Error{
+		code:       CodeInternal,
+		message:    message,
+		extensions: map[string]interface{}{"correlationId": id},
+		cause:      err,
+	}
+}
+
+// With adds an entry to the extensions of the error and returns the error.
+func (e *Error) With(key string, value interface{}) *Error {
+	if e.extensions == nil {
+		e.extensions = make(map[string]interface{})
+	}
+	e.extensions[key] = value
+	return e
+}
+
+func (e *Error) Error() string { return e.message }
+
+// Code returns the code of the error.
+func (e *Error) Code() string { return e.code }
+
+// Unwrap returns the error that caused an internal error.
+func (e *Error) Unwrap() error { return e.cause }
+
+// Extensions returns the code of the error and its extra entries.
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	for key, value := range e.extensions {
+		extensions[key] = value
+	}
+	return extensions
+}
+
+// CodeOf returns the code of an error, or an empty string for errors that have none,
+// whose messages may not be safe to show to clients.
+func CodeOf(err error) string {
+	var coded interface{ Extensions() map[string]interface{} }
+	if !errors.As(err, &coded) {
+		return ""
+	}
+	code, _ := coded.Extensions()["code"].(string)
+	return code
+}
+
+// Safe returns err if it has a code, and an internal error that hides its details otherwise.
+func Safe(ctx context.Context, err error) error {
+	if CodeOf(err) != "" {
+		return err
+	}
+	return Internal(ctx, err, "internal_error")
+}
+
+// WithCorrelationID returns a copy of ctx that carries the correlation id of a request.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, constant.CorrelationId, id) // NOSONAR
+}
+
+// CorrelationID returns the correlation id of the request of ctx.
+// Outside a request, a new id is returned for each call.
+func CorrelationID(ctx context.Context) string {
+	if id, ok := ctx.Value(constant.CorrelationId).(string); ok && id != "" {
+		return id
+	}
+	return uuid.New().String()
+}
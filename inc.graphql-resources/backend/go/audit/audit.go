@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"graphqlapplication/constant"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName is the table the audit entries are written to.
+const TableName = "audit_log"
+
+// Actions recorded in the audit trail.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionToggle  = "toggle"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// maskedValue replaces the values of sensitive fields in the audit trail.
+const maskedValue = "******"
+
+// SensitiveFields are the columns whose values are never written to the audit trail.
+// A change to them is still recorded, with both values masked.
+var SensitiveFields = map[string]bool{
+	"password": true,
+}
+
+// Snapshot holds the values of the columns of a record, indexed by column name.
+type Snapshot map[string]interface{}
+
+// NewSnapshot builds the snapshot of a record from the values of its columns.
+// value returns the value of a column of the record, as the cursorValue methods of the resolvers do.
+func NewSnapshot(columns map[string]string, value func(column string) interface{}) Snapshot {
+	snapshot := make(Snapshot, len(columns))
+	for _, column := range columns {
+		snapshot[column] = value(column)
+	}
+	return snapshot
+}
+
+// Load reads the snapshot of a record. It returns nil if the record does not exist or cannot be read.
+func Load(ctx context.Context, db database.Executor, tableName string, primaryKey string, id interface{}, columns ...string) Snapshot {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", strings.Join(columns, ", "), tableName, primaryKey)
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := db.QueryRowContext(ctx, query, id).Scan(dest...); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to read %s %v for the audit trail: %v", tableName, id, err)
+		}
+		return nil
+	}
+
+	snapshot := make(Snapshot, len(columns))
+	for i, column := range columns {
+		switch v := values[i].(type) {
+		case []byte:
+			snapshot[column] = string(v)
+		case time.Time:
+			snapshot[column] = v.Format(constant.DateTimeFormat)
+		default:
+			snapshot[column] = v
+		}
+	}
+	return snapshot
+}
+
+// Entry is a change to a record.
+type Entry struct {
+	Entity   string
+	RecordID interface{}
+	Action   string
+	Before   Snapshot
+	After    Snapshot
+	AdminID  string
+	IP       string
+}
+
+// Record writes an audit entry for a change made through the GraphQL API.
+// The admin and the IP address are taken from the request context.
+func Record(ctx context.Context, db database.Executor, entity string, recordID interface{}, action string, before Snapshot, after Snapshot) error {
+	return Write(ctx, db, Entry{
+		Entity:   entity,
+		RecordID: recordID,
+		Action:   action,
+		Before:   before,
+		After:    after,
+		AdminID:  util.AuditAdmin(ctx),
+		IP:       util.AuditIP(ctx),
+	})
+}
+
+// Write writes an audit entry holding the fields that differ between the snapshots before and after the change.
+// Nothing is written for an update that changed no field.
+// db should be the transaction of the change, so the change and its entry are committed together:
+// when the entry cannot be written, the error is returned and the change must be rolled back.
+func Write(ctx context.Context, db database.Executor, entry Entry) error {
+	before, after := Diff(entry.Before, entry.After)
+	if len(before) == 0 && len(after) == 0 && (entry.Action == ActionUpdate || entry.Action == ActionToggle) {
+		return nil
+	}
+	query := fmt.Sprintf("INSERT INTO %s (audit_log_id, entity, record_id, action, before_data, after_data, admin_id, ip_address, time_create) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", TableName)
+	_, err := db.ExecContext(ctx, query,
+		uuid.New().String(),
+		entry.Entity,
+		fmt.Sprint(entry.RecordID),
+		entry.Action,
+		encode(before),
+		encode(after),
+		entry.AdminID,
+		entry.IP,
+		util.AuditTime(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write the audit entry of %s %v: %w", entry.Entity, entry.RecordID, err)
+	}
+	return nil
+}
+
+// Diff returns the fields that differ between two snapshots of a record, with their old and new values.
+// When one of the snapshots is nil, as for created and deleted records, the other one is returned whole.
+func Diff(before Snapshot, after Snapshot) (Snapshot, Snapshot) {
+	if before == nil || after == nil {
+		return mask(before), mask(after)
+	}
+	changedBefore := Snapshot{}
+	changedAfter := Snapshot{}
+	for column, value := range after {
+		old, ok := before[column]
+		if ok && jsonEqual(old, value) {
+			continue
+		}
+		changedBefore[column] = old
+		changedAfter[column] = value
+	}
+	return mask(changedBefore), mask(changedAfter)
+}
+
+// mask returns a copy of a snapshot in which the values of the sensitive fields are hidden.
+func mask(snapshot Snapshot) Snapshot {
+	if snapshot == nil {
+		return nil
+	}
+	masked := make(Snapshot, len(snapshot))
+	for column, value := range snapshot {
+		if SensitiveFields[column] && value != nil {
+			value = maskedValue
+		}
+		masked[column] = value
+	}
+	return masked
+}
+
+// jsonEqual reports whether two values have the same JSON encoding.
+// Snapshots hold pointers to the fields of the models, so the values are compared by their encoding.
+func jsonEqual(a interface{}, b interface{}) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// encode returns the JSON encoding of a snapshot, or nil for an empty snapshot.
+func encode(snapshot Snapshot) interface{} {
+	if len(snapshot) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
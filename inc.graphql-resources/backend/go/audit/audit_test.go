@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	name := "Blue Shirt"
+	sameName := "Blue Shirt"
+	tests := []struct {
+		name       string
+		before     Snapshot
+		after      Snapshot
+		wantBefore Snapshot
+		wantAfter  Snapshot
+	}{
+		{
+			name:       "only the changed fields of an update",
+			before:     Snapshot{"name": "Blue Shirt", "price": 10, "active": true},
+			after:      Snapshot{"name": "Red Shirt", "price": 10, "active": true},
+			wantBefore: Snapshot{"name": "Blue Shirt"},
+			wantAfter:  Snapshot{"name": "Red Shirt"},
+		},
+		{
+			name:       "pointers compared by their values",
+			before:     Snapshot{"name": &name},
+			after:      Snapshot{"name": &sameName},
+			wantBefore: Snapshot{},
+			wantAfter:  Snapshot{},
+		},
+		{
+			name:       "field added by the update",
+			before:     Snapshot{"name": "Blue Shirt"},
+			after:      Snapshot{"name": "Blue Shirt", "note": "new"},
+			wantBefore: Snapshot{"note": nil},
+			wantAfter:  Snapshot{"note": "new"},
+		},
+		{
+			name:      "whole snapshot of a created record",
+			after:     Snapshot{"name": "Blue Shirt", "price": 10},
+			wantAfter: Snapshot{"name": "Blue Shirt", "price": 10},
+		},
+		{
+			name:       "whole snapshot of a deleted record",
+			before:     Snapshot{"name": "Blue Shirt", "price": 10},
+			wantBefore: Snapshot{"name": "Blue Shirt", "price": 10},
+		},
+		{
+			name:       "changed password masked",
+			before:     Snapshot{"username": "admin", "password": "old"},
+			after:      Snapshot{"username": "admin", "password": "new"},
+			wantBefore: Snapshot{"password": maskedValue},
+			wantAfter:  Snapshot{"password": maskedValue},
+		},
+		{
+			name:      "password of a created record masked",
+			after:     Snapshot{"username": "admin", "password": "secret"},
+			wantAfter: Snapshot{"username": "admin", "password": maskedValue},
+		},
+		{
+			name:       "password that is not set stays null",
+			before:     Snapshot{"password": nil},
+			wantBefore: Snapshot{"password": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := Diff(tt.before, tt.after)
+			if !reflect.DeepEqual(before, tt.wantBefore) || !reflect.DeepEqual(after, tt.wantAfter) {
+				t.Errorf("Diff() = (%v, %v), want (%v, %v)", before, after, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestMaskKeepsTheSnapshot(t *testing.T) {
+	snapshot := Snapshot{"password": "secret"}
+	mask(snapshot)
+	if snapshot["password"] != "secret" {
+		t.Errorf("mask() changed the snapshot to %v", snapshot)
+	}
+}
+
+// recordingExecutor records the parameters of the statements it runs.
+type recordingExecutor struct {
+	params [][]interface{}
+}
+
+func (e *recordingExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.params = append(e.params, args)
+	return nil, nil
+}
+
+func (e *recordingExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (e *recordingExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestWrite(t *testing.T) {
+	unchanged := Snapshot{"name": "Blue Shirt", "active": true}
+	tests := []struct {
+		name       string
+		entry      Entry
+		written    bool
+		wantBefore interface{}
+		wantAfter  interface{}
+	}{
+		{"update", Entry{Action: ActionUpdate, Before: Snapshot{"name": "Blue Shirt"}, After: Snapshot{"name": "Red Shirt"}}, true, `{"name":"Blue Shirt"}`, `{"name":"Red Shirt"}`},
+		{"update without a change", Entry{Action: ActionUpdate, Before: unchanged, After: unchanged}, false, nil, nil},
+		{"toggle without a change", Entry{Action: ActionToggle, Before: unchanged, After: unchanged}, false, nil, nil},
+		{"create", Entry{Action: ActionCreate, After: Snapshot{"password": "secret"}}, true, nil, `{"password":"******"}`},
+		{"delete", Entry{Action: ActionDelete, Before: unchanged}, true, `{"active":true,"name":"Blue Shirt"}`, nil},
+		{"restore without snapshots", Entry{Action: ActionRestore}, true, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &recordingExecutor{}
+			tt.entry.Entity, tt.entry.RecordID = "product", 7
+			if err := Write(context.Background(), db, tt.entry); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			if written := len(db.params) == 1; written != tt.written {
+				t.Fatalf("Write() wrote %d entries, want an entry: %v", len(db.params), tt.written)
+			}
+			if !tt.written {
+				return
+			}
+			params := db.params[0]
+			if params[1] != "product" || params[2] != "7" || params[3] != tt.entry.Action {
+				t.Errorf("Write() wrote the entry %v of %v %v, want %s of product 7", params[3], params[1], params[2], tt.entry.Action)
+			}
+			if params[4] != tt.wantBefore || params[5] != tt.wantAfter {
+				t.Errorf("Write() wrote (%v, %v), want (%v, %v)", params[4], params[5], tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
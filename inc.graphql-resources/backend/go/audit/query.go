@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"graphqlapplication/database"
+	"graphqlapplication/input"
+)
+
+// Columns maps the fields of the AuditLog type to the columns of the audit table.
+var Columns = input.ColumnMap{
+	"audit_log_id": "audit_log_id",
+	"entity":       "entity",
+	"record_id":    "record_id",
+	"action":       "action",
+	"admin_id":     "admin_id",
+	"ip_address":   "ip_address",
+	"time_create":  "time_create",
+}
+
+// Logs fetches a page of audit entries. Without orderBy, the most recent entries come first.
+func Logs(ctx context.Context, db database.Executor, args input.ListArgs, dialect database.Dialect) (*PageResolver, error) {
+	query, err := input.BuildListQuery(ctx, args, Columns, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return fetch(ctx, db, query, dialect)
+}
+
+// History fetches a page of the audit entries of a single record, most recent first.
+func History(ctx context.Context, db database.Executor, entity string, recordID interface{}, args input.ListArgs, dialect database.Dialect) (*PageResolver, error) {
+	query, err := input.BuildListQuery(ctx, args, Columns, dialect)
+	if err != nil {
+		return nil, err
+	}
+	query.Where, query.Params = input.WhereIn(query.Where, query.Params, "entity", []interface{}{entity})
+	query.Where, query.Params = input.WhereIn(query.Where, query.Params, "record_id", []interface{}{fmt.Sprint(recordID)})
+	return fetch(ctx, db, query, dialect)
+}
+
+// fetch counts the audit entries matching a query and fetches a page of them.
+func fetch(ctx context.Context, db database.Executor, query input.ListQuery, dialect database.Dialect) (*PageResolver, error) {
+	var total int32
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", TableName, query.Where)
+	if err := db.QueryRowContext(ctx, countQuery, query.Params...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	orderSQL := query.Order
+	if orderSQL == "" {
+		orderSQL = "ORDER BY time_create DESC, audit_log_id DESC"
+	}
+	pageSQL, pageParams := dialect.Paginate(orderSQL, query.Limit, query.Offset)
+	queryParams := append(query.Params, pageParams...)
+	sqlQuery := fmt.Sprintf("SELECT audit_log_id, entity, record_id, action, before_data, after_data, admin_id, ip_address, time_create FROM %s %s %s", TableName, query.Where, pageSQL)
+	rows, err := db.QueryContext(ctx, sqlQuery, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*LogResolver{}
+	for rows.Next() {
+		item := &LogResolver{}
+		err := rows.Scan(
+			&item.auditLogID,
+			&item.entity,
+			&item.recordID,
+			&item.action,
+			&item.beforeData,
+			&item.afterData,
+			&item.adminID,
+			&item.ipAddress,
+			&item.timeCreate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	totalPages := int32(0)
+	if total > 0 && query.Limit > 0 {
+		totalPages = (total + query.Limit - 1) / query.Limit
+	}
+	return &PageResolver{
+		items:      items,
+		total:      total,
+		limit:      query.Limit,
+		page:       query.Page,
+		totalPages: totalPages,
+		hasNext:    query.Page < totalPages,
+		hasPrev:    query.Page > 1,
+	}, nil
+}
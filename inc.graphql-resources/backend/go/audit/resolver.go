@@ -0,0 +1,43 @@
+package audit
+
+// LogResolver contains a single audit entry.
+type LogResolver struct {
+	auditLogID string
+	entity     string
+	recordID   string
+	action     string
+	beforeData *string
+	afterData  *string
+	adminID    *string
+	ipAddress  *string
+	timeCreate *string
+}
+
+// PageResolver contains a page of audit entries.
+type PageResolver struct {
+	items      []*LogResolver
+	total      int32
+	limit      int32
+	page       int32
+	totalPages int32
+	hasNext    bool
+	hasPrev    bool
+}
+
+func (r *LogResolver) AuditLogId() string  { return r.auditLogID }
+func (r *LogResolver) Entity() string      { return r.entity }
+func (r *LogResolver) RecordId() string    { return r.recordID }
+func (r *LogResolver) Action() string      { return r.action }
+func (r *LogResolver) BeforeData() *string { return r.beforeData }
+func (r *LogResolver) AfterData() *string  { return r.afterData }
+func (r *LogResolver) AdminId() *string    { return r.adminID }
+func (r *LogResolver) IpAddress() *string  { return r.ipAddress }
+func (r *LogResolver) TimeCreate() *string { return r.timeCreate }
+
+func (r *PageResolver) Items() *[]*LogResolver { return &r.items }
+func (r *PageResolver) Total() *int32          { return &r.total }
+func (r *PageResolver) Limit() *int32          { return &r.limit }
+func (r *PageResolver) Page() *int32           { return &r.page }
+func (r *PageResolver) TotalPages() *int32     { return &r.totalPages }
+func (r *PageResolver) HasNext() *bool         { return &r.hasNext }
+func (r *PageResolver) HasPrevious() *bool     { return &r.hasPrev }
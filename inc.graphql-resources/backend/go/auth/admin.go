@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"graphqlapplication/constant"
+)
+
+// Admin describes the logged-in admin of the current request.
+type Admin struct {
+	AdminID      string
+	AdminLevelID string
+}
+
+// WithAdmin returns a copy of the context carrying the given admin.
+func WithAdmin(ctx context.Context, admin *Admin) context.Context {
+	return context.WithValue(ctx, constant.CurrentAdmin, admin) // NOSONAR
+}
+
+// AdminFromContext returns the logged-in admin stored in the context,
+// or nil if the request is not authenticated.
+func AdminFromContext(ctx context.Context) *Admin {
+	admin, _ := ctx.Value(constant.CurrentAdmin).(*Admin)
+	return admin
+}
+
+// LoadAdmin fetches the admin with the given ID together with its admin level.
+// It returns sql.ErrNoRows if the admin does not exist or is not active.
+func LoadAdmin(ctx context.Context, db *sql.DB, adminID string) (*Admin, error) {
+	var adminLevelID sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT admin_level_id FROM admin WHERE admin_id = ? AND active = ?", adminID, true).Scan(&adminLevelID)
+	if err != nil {
+		return nil, err
+	}
+	return &Admin{AdminID: adminID, AdminLevelID: adminLevelID.String}, nil
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Action is an operation that can be granted to an admin level on an entity.
+type Action string
+
+const (
+	ActionList   Action = "list"
+	ActionDetail Action = "detail"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionToggle Action = "toggle"
+)
+
+// Actions lists all actions in the order they are displayed on the permission page.
+var Actions = []Action{ActionList, ActionDetail, ActionCreate, ActionUpdate, ActionDelete, ActionToggle}
+
+// Permission holds the actions granted to one admin level on one entity.
+// It corresponds to a row of the admin_level_permission table.
+type Permission struct {
+	Entity        string
+	AllowedList   bool
+	AllowedDetail bool
+	AllowedCreate bool
+	AllowedUpdate bool
+	AllowedDelete bool
+	AllowedToggle bool
+}
+
+// Allows reports whether the given action is granted.
+func (p *Permission) Allows(action Action) bool {
+	if p == nil {
+		return false
+	}
+	switch action {
+	case ActionList:
+		return p.AllowedList
+	case ActionDetail:
+		return p.AllowedDetail
+	case ActionCreate:
+		return p.AllowedCreate
+	case ActionUpdate:
+		return p.AllowedUpdate
+	case ActionDelete:
+		return p.AllowedDelete
+	case ActionToggle:
+		return p.AllowedToggle
+	}
+	return false
+}
+
+// Set grants or revokes the given action.
+func (p *Permission) Set(action Action, allowed bool) {
+	switch action {
+	case ActionList:
+		p.AllowedList = allowed
+	case ActionDetail:
+		p.AllowedDetail = allowed
+	case ActionCreate:
+		p.AllowedCreate = allowed
+	case ActionUpdate:
+		p.AllowedUpdate = allowed
+	case ActionDelete:
+		p.AllowedDelete = allowed
+	case ActionToggle:
+		p.AllowedToggle = allowed
+	}
+}
+
+// permissionCacheTTL is how long the permissions of an admin level are kept in memory.
+const permissionCacheTTL = time.Minute
+
+type cachedPermissions struct {
+	permissions map[string]*Permission
+	loadedAt    time.Time
+}
+
+var (
+	permissionCache   = make(map[string]cachedPermissions)
+	permissionCacheMu sync.RWMutex
+)
+
+// IsPermissionRequired reports whether access control is enforced.
+// It is only enforced if the REQUIRE_PERMISSION environment variable is set to "true".
+func IsPermissionRequired() bool {
+	return os.Getenv("REQUIRE_PERMISSION") == "true"
+}
+
+// IsSuperuser reports whether the admin belongs to the superuser level, which is granted every action.
+// The level ID is read from the SUPERUSER_LEVEL_ID environment variable and defaults to "superuser".
+func IsSuperuser(admin *Admin) bool {
+	if admin == nil {
+		return false
+	}
+	superuserLevelID := os.Getenv("SUPERUSER_LEVEL_ID")
+	if superuserLevelID == "" {
+		superuserLevelID = "superuser"
+	}
+	return admin.AdminLevelID == superuserLevelID
+}
+
+// IsAllowed reports whether the admin may perform the action on the entity.
+func IsAllowed(ctx context.Context, db database.Executor, admin *Admin, entity string, action Action) bool {
+	if !IsPermissionRequired() || IsSuperuser(admin) {
+		return true
+	}
+	if admin == nil || admin.AdminLevelID == "" {
+		return false
+	}
+	permissions, err := LoadPermissions(ctx, db, admin.AdminLevelID)
+	if err != nil {
+		log.Printf("Failed to load permissions for admin level %s: %v", admin.AdminLevelID, err)
+		return false
+	}
+	return permissions[entity].Allows(action)
+}
+
+// Authorize checks whether the logged-in admin stored in the context may perform the action on the entity.
+// It returns a translated "forbidden" error if the action is denied.
+func Authorize(ctx context.Context, db database.Executor, entity string, action Action) error {
+	if !IsAllowed(ctx, db, AdminFromContext(ctx), entity, action) {
+		return apperror.Forbidden(util.T(ctx, "forbidden"))
+	}
+	return nil
+}
+
+// LoadPermissions returns the permissions of an admin level indexed by entity name.
+// Results are cached for a short time; call InvalidatePermissions after changing them.
+func LoadPermissions(ctx context.Context, db database.Executor, adminLevelID string) (map[string]*Permission, error) {
+	permissionCacheMu.RLock()
+	cached, ok := permissionCache[adminLevelID]
+	permissionCacheMu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < permissionCacheTTL {
+		return cached.permissions, nil
+	}
+
+	query := `SELECT entity, allowed_list, allowed_detail, allowed_create, allowed_update, allowed_delete, allowed_toggle
+		FROM admin_level_permission WHERE admin_level_id = ?`
+	rows, err := db.QueryContext(ctx, query, adminLevelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make(map[string]*Permission)
+	for rows.Next() {
+		var entity string
+		var list, detail, create, update, del, toggle sql.NullBool
+		if err := rows.Scan(&entity, &list, &detail, &create, &update, &del, &toggle); err != nil {
+			return nil, err
+		}
+		permissions[entity] = &Permission{
+			Entity:        entity,
+			AllowedList:   list.Bool,
+			AllowedDetail: detail.Bool,
+			AllowedCreate: create.Bool,
+			AllowedUpdate: update.Bool,
+			AllowedDelete: del.Bool,
+			AllowedToggle: toggle.Bool,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permissionCacheMu.Lock()
+	permissionCache[adminLevelID] = cachedPermissions{permissions: permissions, loadedAt: time.Now()}
+	permissionCacheMu.Unlock()
+	return permissions, nil
+}
+
+// InvalidatePermissions removes the cached permissions of an admin level.
+func InvalidatePermissions(adminLevelID string) {
+	permissionCacheMu.Lock()
+	delete(permissionCache, adminLevelID)
+	permissionCacheMu.Unlock()
+}
@@ -0,0 +1,314 @@
+package complexity
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/input"
+	"graphqlapplication/util"
+	"math"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+)
+
+// Limits are the largest depth and cost of the requests accepted by an Analyzer. Zero means no limit.
+type Limits struct {
+	MaxDepth int
+	MaxCost  int
+}
+
+// Result holds the depth and the cost of a request.
+type Result struct {
+	Depth int
+	Cost  int
+}
+
+// field describes a field of an object type of the schema.
+type field struct {
+	// typeName is the name of the type returned by the field, without list and non-null wrappers.
+	typeName string
+	// pageArgs are the arguments that set the number of items returned by the field, such as limit or first.
+	pageArgs []string
+	// defaultSize is the number of items returned when none of pageArgs is given.
+	defaultSize int
+}
+
+// pageArgs are the arguments that set the number of items returned by list queries and connections.
+var pageArgs = []string{"limit", "size", "first", "last"}
+
+// Analyzer computes the depth and the cost of GraphQL requests before they run, and rejects the requests
+// that exceed its limits.
+//
+// The depth is the number of nested levels of fields. The cost estimates the number of records a request
+// may read: each field that has a selection set costs one, multiplied by the page size of the fields
+// that return pages of items, such as the limit of a list query or the first of a connection.
+// Fields without a selection set are free. Each page size must not exceed input.MaxPageSize.
+type Analyzer struct {
+	limits    Limits
+	fields    map[string]map[string]field
+	rootTypes map[string]string
+}
+
+// NewAnalyzer creates an analyzer for the requests of a schema.
+func NewAnalyzer(schema *graphql.Schema, limits Limits) *Analyzer {
+	inspected := schema.Inspect()
+	a := &Analyzer{
+		limits:    limits,
+		fields:    make(map[string]map[string]field),
+		rootTypes: make(map[string]string),
+	}
+	for operationType, t := range map[string]*introspection.Type{
+		"query":        inspected.QueryType(),
+		"mutation":     inspected.MutationType(),
+		"subscription": inspected.SubscriptionType(),
+	} {
+		if t != nil && t.Name() != nil {
+			a.rootTypes[operationType] = *t.Name()
+		}
+	}
+
+	for _, t := range inspected.Types() {
+		if t.Name() == nil || (t.Kind() != "OBJECT" && t.Kind() != "INTERFACE") {
+			continue
+		}
+		fields := t.Fields(&struct{ IncludeDeprecated bool }{true})
+		if fields == nil {
+			continue
+		}
+		typeFields := make(map[string]field)
+		for _, f := range *fields {
+			info := field{typeName: namedType(f.Type()), defaultSize: int(input.DefaultLimit)}
+			for _, arg := range f.Args() {
+				for _, name := range pageArgs {
+					if arg.Name() == name {
+						info.pageArgs = append(info.pageArgs, name)
+					}
+				}
+				if arg.Name() == "first" {
+					info.defaultSize = int(input.DefaultConnectionSize)
+				}
+			}
+			typeFields[f.Name()] = info
+		}
+		a.fields[*t.Name()] = typeFields
+	}
+	return a
+}
+
+// namedType returns the name of a type without its list and non-null wrappers.
+func namedType(t *introspection.Type) string {
+	for t != nil && t.Name() == nil {
+		t = t.OfType()
+	}
+	if t == nil {
+		return ""
+	}
+	return *t.Name()
+}
+
+// Check computes the depth and the cost of a request, and returns an error if they exceed the limits
+// or if a page size exceeds input.MaxPageSize.
+// Requests that cannot be parsed, or whose operation cannot be found, are rejected too,
+// as their depth and cost are unknown.
+func (a *Analyzer) Check(ctx context.Context, query string, operationName string, variables map[string]interface{}) (Result, error) {
+	doc, err := parseDocument(query)
+	if err != nil {
+		return Result{}, apperror.Validation(util.T(ctx, "invalid_query", err.Error()))
+	}
+	op := doc.operation(operationName)
+	if op == nil && operationName != "" {
+		return Result{}, apperror.Validation(util.T(ctx, "operation_not_found", operationName))
+	}
+	if op == nil {
+		return Result{}, apperror.Validation(util.T(ctx, "operation_name_required"))
+	}
+
+	w := &walker{
+		ctx:       ctx,
+		analyzer:  a,
+		doc:       doc,
+		defaults:  op.defaults,
+		variables: variables,
+		visiting:  make(map[string]bool),
+	}
+	depth, cost, err := w.selectionSet(a.rootTypes[op.kind], op.selections, 1)
+	result := Result{Depth: depth, Cost: cost}
+	if err != nil {
+		return result, err
+	}
+	if a.limits.MaxCost > 0 && cost > a.limits.MaxCost {
+		return result, a.tooComplex(ctx, cost)
+	}
+	return result, nil
+}
+
+// walker computes the depth and the cost of the selections of an operation.
+type walker struct {
+	ctx       context.Context
+	analyzer  *Analyzer
+	doc       *document
+	defaults  map[string]interface{}
+	variables map[string]interface{}
+	// visiting holds the fragments being walked, so fragments that spread themselves are not walked forever.
+	visiting map[string]bool
+}
+
+// selectionSet returns the depth and the cost of the selections of a field that returns typeName.
+// level is the depth of the selections.
+func (w *walker) selectionSet(typeName string, selections []*selection, level int) (int, int, error) {
+	depth, cost := 0, 0
+	for _, sel := range selections {
+		var selDepth, selCost int
+		var err error
+		switch {
+		case sel.spread != "":
+			frag := w.doc.fragments[sel.spread]
+			if frag == nil || w.visiting[sel.spread] {
+				continue
+			}
+			w.visiting[sel.spread] = true
+			selDepth, selCost, err = w.selectionSet(w.fragmentType(frag.typeName, typeName), frag.selections, level)
+			delete(w.visiting, sel.spread)
+		case sel.field == "":
+			selDepth, selCost, err = w.selectionSet(w.fragmentType(sel.typeName, typeName), sel.selections, level)
+		default:
+			selDepth, selCost, err = w.field(typeName, sel, level)
+		}
+		if selDepth > depth {
+			depth = selDepth
+		}
+		cost = add(cost, selCost)
+		if err != nil {
+			return depth, cost, err
+		}
+		if limit := w.analyzer.limits.MaxCost; limit > 0 && cost > limit {
+			return depth, cost, w.analyzer.tooComplex(w.ctx, cost)
+		}
+	}
+	return depth, cost, nil
+}
+
+// field returns the depth and the cost of a selected field of typeName.
+func (w *walker) field(typeName string, sel *selection, level int) (int, int, error) {
+	if strings.HasPrefix(sel.field, "__") {
+		// Introspection is not limited
+		return 0, 0, nil
+	}
+	if limit := w.analyzer.limits.MaxDepth; limit > 0 && level > limit {
+		return level, 0, apperror.Validation(util.T(w.ctx, "query_too_deep", limit)).
+			With("depth", level).
+			With("maxDepth", limit)
+	}
+	info, ok := w.analyzer.fields[typeName][sel.field]
+	if !ok || len(sel.selections) == 0 {
+		// Unknown fields are reported by graphql-go
+		return level, 0, nil
+	}
+
+	size, err := w.pageSize(sel, info)
+	if err != nil {
+		return level, 0, err
+	}
+	depth, cost, err := w.selectionSet(info.typeName, sel.selections, level+1)
+	if err != nil {
+		return depth, cost, err
+	}
+	return depth, multiply(size, add(1, cost)), nil
+}
+
+// pageSize returns the number of items a field may return: the value of its page arguments,
+// or its default page size. Fields that do not return pages count as one item.
+func (w *walker) pageSize(sel *selection, info field) (int, error) {
+	if len(info.pageArgs) == 0 {
+		return 1, nil
+	}
+	size, given := 0, false
+	for _, name := range info.pageArgs {
+		if n, ok := w.intValue(sel.arguments[name]); ok {
+			given = true
+			if n > size {
+				size = n
+			}
+		}
+	}
+	if !given {
+		return info.defaultSize, nil
+	}
+	if size == 0 {
+		// The query still runs to count the items
+		size = 1
+	}
+	if limit := int(input.MaxPageSize); limit > 0 && size > limit {
+		return size, apperror.Validation(util.T(w.ctx, "page_size_too_large", sel.field, limit)).
+			With("pageSize", size).
+			With("maxPageSize", limit)
+	}
+	return size, nil
+}
+
+// intValue returns the value of an integer argument, reading variables and their default values.
+// Null and negative values are not page sizes; the resolvers reject page sizes below one
+// before running any query, so they cost nothing.
+func (w *walker) intValue(value interface{}) (int, bool) {
+	if name, ok := value.(variable); ok {
+		var set bool
+		value, set = w.variables[string(name)]
+		if !set {
+			value = w.defaults[string(name)]
+		}
+	}
+	var n float64
+	switch v := value.(type) {
+	case int64:
+		n = float64(v)
+	case int32:
+		n = float64(v)
+	case int:
+		n = float64(v)
+	case float64:
+		// Variables decoded from JSON
+		n = v
+	default:
+		return 0, false
+	}
+	if n < 0 {
+		return 0, false
+	}
+	if n > math.MaxInt32 {
+		return math.MaxInt32, true
+	}
+	return int(n), true
+}
+
+// fragmentType returns the type the selections of a fragment apply to:
+// its type condition if the schema has it, or the type of the enclosing selection set.
+func (w *walker) fragmentType(condition string, typeName string) string {
+	if _, ok := w.analyzer.fields[condition]; ok {
+		return condition
+	}
+	return typeName
+}
+
+// tooComplex creates the error returned when the cost of a request exceeds the limit.
+func (a *Analyzer) tooComplex(ctx context.Context, cost int) error {
+	return apperror.Validation(util.T(ctx, "query_too_complex", a.limits.MaxCost)).
+		With("cost", cost).
+		With("maxCost", a.limits.MaxCost)
+}
+
+// add adds two costs, stopping at the largest int.
+func add(a int, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// multiply multiplies two costs, stopping at the largest int.
+func multiply(a int, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
@@ -0,0 +1,202 @@
+package complexity
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const testSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	items(limit: Int, page: Int): [Item!]!
+	itemConnection(first: Int, after: String, last: Int): ItemConnection!
+	item(id: ID!): Item
+}
+
+type Item {
+	id: ID!
+	name: String
+	children(limit: Int): [Item!]!
+	parent: Item
+}
+
+type ItemConnection {
+	edges: [ItemEdge!]!
+}
+
+type ItemEdge {
+	node: Item!
+	cursor: String!
+}
+`
+
+func TestCheck(t *testing.T) {
+	schema := graphql.MustParseSchema(testSchema, nil)
+	analyzer := NewAnalyzer(schema, Limits{MaxDepth: 4, MaxCost: 50})
+
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]interface{}
+		depth         int
+		cost          int
+		wantErr       bool
+	}{
+		{
+			name:  "list with the default page size",
+			query: `{ items { id name } }`,
+			depth: 2,
+			cost:  10,
+		},
+		{
+			name:  "nested lists multiply their page sizes",
+			query: `{ items(limit: 5) { children(limit: 3) { id } } }`,
+			depth: 3,
+			cost:  20,
+		},
+		{
+			name:  "connection with first",
+			query: `{ itemConnection(first: 4) { edges { node { id } } } }`,
+			depth: 4,
+			cost:  12,
+		},
+		{
+			name:      "page size from a variable",
+			query:     `query Items($n: Int = 7) { items(limit: $n) { id } }`,
+			variables: map[string]interface{}{"n": float64(3)},
+			depth:     2,
+			cost:      3,
+		},
+		{
+			name:  "page size from the default value of a variable",
+			query: `query Items($n: Int = 7) { items(limit: $n) { id } }`,
+			depth: 2,
+			cost:  7,
+		},
+		{
+			name:  "negative page size counts as no page size",
+			query: `{ items(limit: -1) { id } }`,
+			depth: 2,
+			cost:  10,
+		},
+		{
+			name:  "fragments",
+			query: `{ ...Items } fragment Items on Query { items(limit: 2) { id } }`,
+			depth: 2,
+			cost:  2,
+		},
+		{
+			name:  "fragment that spreads itself",
+			query: `{ item(id: 1) { ...Parent } } fragment Parent on Item { parent { id ...Parent } }`,
+			depth: 3,
+			cost:  2,
+		},
+		{
+			name:          "named operation",
+			query:         `query A { items(limit: 1) { id } } query B { items(limit: 2) { id } }`,
+			operationName: "B",
+			depth:         2,
+			cost:          2,
+		},
+		{
+			name:  "introspection is free",
+			query: `{ __schema { types { name fields { name } } } }`,
+			depth: 0,
+			cost:  0,
+		},
+		{
+			name:    "too deep",
+			query:   `{ items(limit: 1) { children(limit: 1) { children(limit: 1) { children(limit: 1) { id } } } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "too complex",
+			query:   `{ items(limit: 10) { children(limit: 10) { id } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "too complex with an alias that is not ASCII",
+			query:   `{ é: items(limit: 100) { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "page size larger than the maximum",
+			query:   `{ items(limit: 101) { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			query:   `{ items(limit: 100) { id }`,
+			wantErr: true,
+		},
+		{
+			name:    "several operations without a name",
+			query:   `query A { items { id } } query B { items { id } }`,
+			wantErr: true,
+		},
+		{
+			name:          "unknown operation",
+			query:         `query A { items { id } }`,
+			operationName: "B",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := analyzer.Check(context.Background(), tt.query, tt.operationName, tt.variables)
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("Check() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() failed: %v", err)
+			}
+			if result.Depth != tt.depth || result.Cost != tt.cost {
+				t.Errorf("Check() = %+v, want {Depth:%d Cost:%d}", result, tt.depth, tt.cost)
+			}
+		})
+	}
+}
+
+func TestParseDocumentNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		field   string
+		wantErr bool
+	}{
+		{"ASCII name", `{ items { id } }`, "items", false},
+		{"alias with a letter that is not ASCII", `{ é: items { id } }`, "items", false},
+		{"alias with digits that are not ASCII", `{ a٣: items { id } }`, "items", false},
+		{"byte order mark", "\ufeff{ items { id } }", "items", false},
+		{"name starting with a digit", `{ 1items { id } }`, "", true},
+		{"symbol in a name", `{ it€ms { id } }`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseDocument(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parseDocument() succeeded, want a syntax error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDocument() failed: %v", err)
+			}
+			op := doc.operation("")
+			if op == nil || len(op.selections) != 1 || op.selections[0].field != tt.field {
+				t.Errorf("parseDocument() did not select the field %q", tt.field)
+			}
+		})
+	}
+}
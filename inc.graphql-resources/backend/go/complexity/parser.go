@@ -0,0 +1,470 @@
+package complexity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The parser reads the parts of a GraphQL request that are needed to compute its cost:
+// operations, fragments, fields, their arguments and their selection sets.
+// Directives and types are read but ignored. graphql-go parses and validates the request again
+// before running it, and reports the errors that are not reported here.
+
+// document is a parsed GraphQL request.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query, a mutation or a subscription of a document.
+type operation struct {
+	// kind is "query", "mutation" or "subscription".
+	kind       string
+	name       string
+	defaults   map[string]interface{}
+	selections []*selection
+}
+
+// fragment is a named fragment of a document.
+type fragment struct {
+	typeName   string
+	selections []*selection
+}
+
+// selection is a field, a fragment spread or an inline fragment.
+type selection struct {
+	// field is the name of a selected field; it is empty for fragments.
+	field      string
+	arguments  map[string]interface{}
+	spread     string
+	typeName   string
+	selections []*selection
+}
+
+// variable is a reference to a variable of the operation in the value of an argument.
+type variable string
+
+// syntaxError is raised by the parser when a request is not valid GraphQL.
+type syntaxError struct {
+	message string
+}
+
+func (e syntaxError) Error() string { return e.message }
+
+// operation returns the operation of a document that a request runs, or nil if there is none.
+// A document with several operations needs the name of one.
+func (d *document) operation(name string) *operation {
+	if name == "" {
+		if len(d.operations) != 1 {
+			return nil
+		}
+		return d.operations[0]
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// Kinds of the tokens of a GraphQL document.
+const (
+	tokenEOF = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+}
+
+// parser reads a GraphQL document one token at a time.
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// parseDocument parses a GraphQL request.
+func parseDocument(src string) (doc *document, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(syntaxError)
+			if !ok {
+				panic(r)
+			}
+			err = syntaxErr
+		}
+	}()
+
+	p := &parser{src: strings.TrimPrefix(src, "\ufeff")}
+	p.next()
+	doc = &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.is(tokenPunctuator, "{"):
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: p.parseSelectionSet()})
+		case p.is(tokenName, "fragment"):
+			p.next()
+			name := p.expect(tokenName, "")
+			p.expect(tokenName, "on")
+			frag := &fragment{typeName: p.expect(tokenName, "")}
+			p.parseDirectives()
+			frag.selections = p.parseSelectionSet()
+			doc.fragments[name] = frag
+		case p.is(tokenName, "query"), p.is(tokenName, "mutation"), p.is(tokenName, "subscription"):
+			doc.operations = append(doc.operations, p.parseOperation())
+		default:
+			p.fail()
+		}
+	}
+	return doc, nil
+}
+
+// parseOperation parses an operation that starts with its type.
+func (p *parser) parseOperation() *operation {
+	op := &operation{kind: p.tok.value, defaults: make(map[string]interface{})}
+	p.next()
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		p.next()
+	}
+	if p.skip(tokenPunctuator, "(") {
+		for !p.skip(tokenPunctuator, ")") {
+			p.expect(tokenPunctuator, "$")
+			name := p.expect(tokenName, "")
+			p.expect(tokenPunctuator, ":")
+			p.parseType()
+			if p.skip(tokenPunctuator, "=") {
+				op.defaults[name] = p.parseValue()
+			}
+			p.parseDirectives()
+		}
+	}
+	p.parseDirectives()
+	op.selections = p.parseSelectionSet()
+	return op
+}
+
+// parseType skips the type of a variable.
+func (p *parser) parseType() {
+	if p.skip(tokenPunctuator, "[") {
+		p.parseType()
+		p.expect(tokenPunctuator, "]")
+	} else {
+		p.expect(tokenName, "")
+	}
+	p.skip(tokenPunctuator, "!")
+}
+
+// parseSelectionSet parses a selection set, including its braces.
+func (p *parser) parseSelectionSet() []*selection {
+	p.expect(tokenPunctuator, "{")
+	var selections []*selection
+	for !p.skip(tokenPunctuator, "}") {
+		selections = append(selections, p.parseSelection())
+	}
+	return selections
+}
+
+// parseSelection parses a field, a fragment spread or an inline fragment.
+func (p *parser) parseSelection() *selection {
+	if p.skip(tokenPunctuator, "...") {
+		sel := &selection{}
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			sel.spread = p.tok.value
+			p.next()
+			p.parseDirectives()
+			return sel
+		}
+		if p.skip(tokenName, "on") {
+			sel.typeName = p.expect(tokenName, "")
+		}
+		p.parseDirectives()
+		sel.selections = p.parseSelectionSet()
+		return sel
+	}
+
+	sel := &selection{field: p.expect(tokenName, "")}
+	if p.skip(tokenPunctuator, ":") {
+		// The name read first was an alias
+		sel.field = p.expect(tokenName, "")
+	}
+	if p.is(tokenPunctuator, "(") {
+		sel.arguments = p.parseArguments()
+	}
+	p.parseDirectives()
+	if p.is(tokenPunctuator, "{") {
+		sel.selections = p.parseSelectionSet()
+	}
+	return sel
+}
+
+// parseArguments parses the arguments of a field or a directive, including their parentheses.
+func (p *parser) parseArguments() map[string]interface{} {
+	p.expect(tokenPunctuator, "(")
+	arguments := make(map[string]interface{})
+	for !p.skip(tokenPunctuator, ")") {
+		name := p.expect(tokenName, "")
+		p.expect(tokenPunctuator, ":")
+		arguments[name] = p.parseValue()
+	}
+	return arguments
+}
+
+// parseDirectives skips the directives that follow an element.
+func (p *parser) parseDirectives() {
+	for p.skip(tokenPunctuator, "@") {
+		p.expect(tokenName, "")
+		if p.is(tokenPunctuator, "(") {
+			p.parseArguments()
+		}
+	}
+}
+
+// parseValue parses a value. Integers are returned as int64, variables as variable
+// and enum values as strings.
+func (p *parser) parseValue() interface{} {
+	tok := p.tok
+	switch {
+	case p.skip(tokenPunctuator, "$"):
+		return variable(p.expect(tokenName, ""))
+	case p.skip(tokenPunctuator, "["):
+		list := []interface{}{}
+		for !p.skip(tokenPunctuator, "]") {
+			list = append(list, p.parseValue())
+		}
+		return list
+	case p.skip(tokenPunctuator, "{"):
+		object := make(map[string]interface{})
+		for !p.skip(tokenPunctuator, "}") {
+			name := p.expect(tokenName, "")
+			p.expect(tokenPunctuator, ":")
+			object[name] = p.parseValue()
+		}
+		return object
+	case tok.kind == tokenInt:
+		p.next()
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			// Too large for an Int; graphql-go reports it
+			return nil
+		}
+		return n
+	case tok.kind == tokenFloat:
+		p.next()
+		f, _ := strconv.ParseFloat(tok.value, 64)
+		return f
+	case tok.kind == tokenString:
+		p.next()
+		return tok.value
+	case tok.kind == tokenName:
+		p.next()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return tok.value
+	}
+	p.fail()
+	return nil
+}
+
+// is tells whether the current token has a kind and a value.
+func (p *parser) is(kind int, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+// skip reads the current token if it has a kind and a value, and tells whether it did.
+func (p *parser) skip(kind int, value string) bool {
+	if !p.is(kind, value) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+// expect reads the current token and returns its value. It fails if the token does not have the kind,
+// or the value unless the value is empty.
+func (p *parser) expect(kind int, value string) string {
+	if p.tok.kind != kind || (value != "" && p.tok.value != value) {
+		p.fail()
+	}
+	tok := p.tok
+	p.next()
+	return tok.value
+}
+
+// fail stops the parser at the current token.
+func (p *parser) fail() {
+	if p.tok.kind == tokenEOF {
+		panic(syntaxError{message: "unexpected end of document"})
+	}
+	panic(syntaxError{message: fmt.Sprintf("unexpected %q at offset %d", p.tok.value, p.pos)})
+}
+
+// next reads the next token, skipping white space, commas and comments.
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokenEOF}
+		return
+	}
+
+	start := p.pos
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunctuator, value: "..."}
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunctuator, value: string(c)}
+	case isNameStart(p.rune()):
+		for p.pos < len(p.src) && isNamePart(p.rune()) {
+			_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			p.pos += size
+		}
+		p.tok = token{kind: tokenName, value: p.src[start:p.pos]}
+	case c == '-' || isDigit(c):
+		p.tok = p.readNumber()
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		p.tok = p.readBlockString()
+	case c == '"':
+		p.tok = p.readString()
+	default:
+		p.tok = token{kind: tokenPunctuator, value: string(p.rune())}
+		p.fail()
+	}
+}
+
+// rune returns the character at the current position.
+func (p *parser) rune() rune {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+// readNumber reads an Int or a Float.
+func (p *parser) readNumber() token {
+	start := p.pos
+	kind := tokenInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	p.readDigits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		p.readDigits()
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		p.readDigits()
+	}
+	return token{kind: kind, value: p.src[start:p.pos]}
+}
+
+// readDigits reads one or more digits.
+func (p *parser) readDigits() {
+	start := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		p.tok = token{kind: tokenPunctuator, value: p.src[start:p.pos]}
+		p.fail()
+	}
+}
+
+// readString reads a quoted string. Its escape sequences are those of JSON.
+func (p *parser) readString() token {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\n' || p.src[p.pos] == '\r' {
+			break
+		}
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+		p.tok = token{kind: tokenEOF}
+		p.fail()
+	}
+	p.pos++
+	var value string
+	if err := json.Unmarshal([]byte(p.src[start:p.pos]), &value); err != nil {
+		p.tok = token{kind: tokenString, value: p.src[start:p.pos]}
+		p.fail()
+	}
+	return token{kind: tokenString, value: value}
+}
+
+// readBlockString reads a string between triple quotes. Its indentation is kept.
+func (p *parser) readBlockString() token {
+	p.pos += 3
+	var value strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			p.tok = token{kind: tokenEOF}
+			p.fail()
+		}
+		if strings.HasPrefix(p.src[p.pos:], `\"""`) {
+			value.WriteString(`"""`)
+			p.pos += 4
+			continue
+		}
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			p.pos += 3
+			return token{kind: tokenString, value: value.String()}
+		}
+		value.WriteByte(p.src[p.pos])
+		p.pos++
+	}
+}
+
+// isNameStart tells whether a name may start with a character.
+// Like graphql-go, which reads names as Go identifiers, names may contain any Unicode letter, not only ASCII ones.
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isNamePart tells whether a name may contain a character after its first one.
+func isNamePart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,74 @@
+package concurrency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+	"reflect"
+)
+
+// NewConflictError creates the error returned when the record of an entity was changed since it was read.
+// current is the version the record has now, or nil if it has none; it is added to the extensions
+// of the GraphQL error, so clients can reload the record.
+func NewConflictError(ctx context.Context, entity string, id interface{}, current *string) error {
+	return apperror.Conflict(util.T(ctx, "record_changed_since_read", entity, id)).With("currentVersion", current)
+}
+
+// Version formats the value of a version column as it is exposed to the clients.
+// Pointers are dereferenced; nil is returned for NULL values.
+func Version(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	version := fmt.Sprint(v.Interface())
+	return &version
+}
+
+// Matches tells whether the current version of a record is the expected one.
+// An empty expected version matches a record without a version.
+func Matches(current *string, expected string) bool {
+	if current == nil {
+		return expected == ""
+	}
+	return *current == expected
+}
+
+// Condition returns the condition that restricts an UPDATE to the row that still has the expected version.
+func Condition(column string, expected string) (string, []interface{}) {
+	if expected == "" {
+		return column + " IS NULL", nil
+	}
+	return column + " = ?", []interface{}{expected}
+}
+
+// Verify is called when an UPDATE restricted by Condition matched no row.
+// It returns a conflict error if the record now has another version; otherwise the record
+// either does not exist or was not changed by the update, and nil is returned.
+func Verify(ctx context.Context, db database.Executor, tableName string, primaryKey string, id interface{}, column string, expected string) error {
+	var value sql.NullString
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", column, tableName, primaryKey)
+	if err := db.QueryRowContext(ctx, query, id).Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	var current *string
+	if value.Valid {
+		current = &value.String
+	}
+	if Matches(current, expected) {
+		return nil
+	}
+	return NewConflictError(ctx, tableName, id, current)
+}
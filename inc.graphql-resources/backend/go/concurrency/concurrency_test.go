@@ -0,0 +1,78 @@
+package concurrency
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestVersion(t *testing.T) {
+	number := int64(3)
+	text := "2024-05-17 08:30:00"
+	tests := []struct {
+		name  string
+		value interface{}
+		want  *string
+	}{
+		{"nil", nil, nil},
+		{"number", int64(3), strPtr("3")},
+		{"pointer to a number", &number, strPtr("3")},
+		{"pointer to text", &text, &text},
+		{"nil pointer", (*int64)(nil), nil},
+		{"nil pointer to time", (*time.Time)(nil), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Version(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Version() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	version := "3"
+	empty := ""
+	tests := []struct {
+		name     string
+		current  *string
+		expected string
+		want     bool
+	}{
+		{"same version", &version, "3", true},
+		{"other version", &version, "2", false},
+		{"record without a version", nil, "", true},
+		{"record without a version expected to have one", nil, "3", false},
+		{"record with a version expected to have none", &version, "", false},
+		{"empty version", &empty, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.current, tt.expected); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   string
+		want       string
+		wantParams []interface{}
+	}{
+		{"expected version", "3", "version = ?", []interface{}{"3"}},
+		{"no expected version", "", "version IS NULL", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, params := Condition("version", tt.expected)
+			if condition != tt.want || !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("Condition() = (%q, %v), want (%q, %v)", condition, params, tt.want, tt.wantParams)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"graphqlapplication/database"
 	"os"
 	"strings"
 )
@@ -9,8 +10,18 @@ import (
 // It is initialized based on the DB_DRIVER environment variable.
 var IsPostgres bool
 
+// Dialect is the SQL dialect of the configured database driver.
+// It is initialized based on the DB_DRIVER environment variable.
+var Dialect database.Dialect = database.MySQL{}
+
 func init() {
-	dbDriver := os.Getenv("DB_DRIVER")
+	InitDriver(os.Getenv("DB_DRIVER"))
+}
+
+// InitDriver sets the driver flags and dialect from the configured driver name.
+// It must be called again after the .env file has been loaded, since init runs before that.
+func InitDriver(dbDriver string) {
+	Dialect = database.DialectFor(dbDriver)
 	dbDriver = strings.ToLower(dbDriver)
-	IsPostgres = strings.Contains(dbDriver, "postgre")
-}
\ No newline at end of file
+	IsPostgres = strings.Contains(dbDriver, "postgre") || dbDriver == "pgsql"
+}
@@ -7,5 +7,10 @@ const (
 	SessionUsername string = "SessionUsername"
 	SessionPassword string = "SessionPassword"
 	SessionAdminId  string = "SessionAdminId"
+	CurrentAdmin    string = "CurrentAdmin"
 	LanguageKey     string = "language"
+	Loaders         string = "Loaders"
+	Transaction     string = "Transaction"
+	CorrelationId   string = "CorrelationId"
+	PendingEvents   string = "PendingEvents"
 )
@@ -2,14 +2,20 @@ package controller
 
 import (
 	"context"
-	"crypto/sha1"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"graphqlapplication/apperror"
+	"graphqlapplication/audit"
+	"graphqlapplication/auth"
+	"graphqlapplication/concurrency"
+	"graphqlapplication/config"
 	"graphqlapplication/constant"
 	"graphqlapplication/systemmodel"
+	"graphqlapplication/trash"
 	"graphqlapplication/util"
 	"strconv"
 	"time"
@@ -135,8 +141,9 @@ func (h *AdminHandler) ListAdmins(w http.ResponseWriter, r *http.Request, adminI
 	totalPages := int(math.Ceil(float64(totalAdmins) / float64(dataLimit)))
 
 	// Get admin data for the current page
-	dataQuery += whereClause + " ORDER BY a.name LIMIT ? OFFSET ?"
-	args = append(args, dataLimit, offset)
+	pageSQL, pageArgs := config.Dialect.Paginate("ORDER BY a.name", dataLimit, offset)
+	dataQuery += whereClause + " " + pageSQL
+	args = append(args, pageArgs...)
 
 	rows, err := h.DB.Query(dataQuery, args...)
 	if err != nil {
@@ -227,7 +234,7 @@ func (h *AdminHandler) getAdminForm(w http.ResponseWriter, r *http.Request, enti
 	}
 
 	// Fetch admin levels
-	levelRows, err := h.DB.QueryContext(ctx, "SELECT admin_level_id, name FROM admin_level WHERE active = 1 ORDER BY sort_order")
+	levelRows, err := h.DB.QueryContext(ctx, "SELECT admin_level_id, name FROM admin_level WHERE active = ? ORDER BY sort_order", true)
 	if err != nil {
 		http.Error(w, util.T(ctx, "failed_to_fetch_admin_levels", err.Error()), http.StatusOK)
 		return
@@ -251,9 +258,9 @@ func (h *AdminHandler) getAdminForm(w http.ResponseWriter, r *http.Request, enti
 	} else {
 		// For edit mode, fetch the existing admin data
 		var admin AdminDetail
-		query := `SELECT admin_id, name, username, email, admin_level_id, active FROM admin WHERE admin_id = ?`
+		query := `SELECT admin_id, name, username, email, admin_level_id, active, time_edit FROM admin WHERE admin_id = ?`
 		err := h.DB.QueryRowContext(ctx, query, entityID).Scan(
-			&admin.AdminID, &admin.Name, &admin.Username, &admin.Email, &admin.AdminLevelID, &admin.Active,
+			&admin.AdminID, &admin.Name, &admin.Username, &admin.Email, &admin.AdminLevelID, &admin.Active, &admin.TimeEdit,
 		)
 
 		if err != nil {
@@ -319,6 +326,10 @@ func (h *AdminHandler) handleGet(w http.ResponseWriter, r *http.Request, adminID
 	// Get the 'adminId' parameter, which is the ID of the admin to be viewed, edited, or have their password changed.
 	entityID := r.URL.Query().Get("adminId")
 
+	if !h.authorize(w, r, adminID, adminViewActions[view]) {
+		return
+	}
+
 	// Route the request based on the 'view' parameter.
 	switch view {
 	case "list", "":
@@ -357,6 +368,9 @@ func (h *AdminHandler) handleGet(w http.ResponseWriter, r *http.Request, adminID
 // handlePost handles POST requests for actions on admins.
 func (h *AdminHandler) handlePost(w http.ResponseWriter, r *http.Request, adminID string) {
 	ctx := r.Context()
+	// Expose the admin and the client address to the trash, as the GraphQL middleware does
+	ctx = context.WithValue(ctx, constant.SessionAdminId, adminID)         // NOSONAR
+	ctx = context.WithValue(ctx, constant.RemoteAddr, util.GetClientIP(r)) // NOSONAR
 	w.Header().Set("Content-Type", "application/json")
 	// Use ParseMultipartForm to handle both multipart/form-data and application/x-www-form-urlencoded
 	// 10 << 20 specifies a maximum of 10 MB for the in-memory part of the form.
@@ -368,20 +382,37 @@ func (h *AdminHandler) handlePost(w http.ResponseWriter, r *http.Request, adminI
 	action := r.FormValue("action")
 	entityID := r.FormValue("adminId")
 
+	if permissionAction, ok := adminPostActions[action]; ok && !h.authorize(w, r, adminID, permissionAction) {
+		return
+	}
+
+	// Change the admin and write its audit entry in one transaction, so both are committed or neither is
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Read the admin before the change, so the audit entry can tell what changed
+	var before audit.Snapshot
+	if entityID != "" {
+		before = audit.Load(ctx, tx, "admin", "admin_id", entityID, adminAuditColumns...)
+	}
+
 	var response map[string]interface{}
-	var err error
 
 	switch action {
 	case "create":
-		response, err = h.createAdmin(ctx, r, adminID)
+		response, err = h.createAdmin(ctx, tx, r, adminID)
 	case "update":
-		response, err = h.updateAdmin(ctx, r, adminID, entityID)
+		response, err = h.updateAdmin(ctx, tx, r, adminID, entityID)
 	case "toggle_active":
-		response, err = h.toggleAdminActive(ctx, adminID, entityID)
+		response, err = h.toggleAdminActive(ctx, tx, adminID, entityID)
 	case "change_password":
-		response, err = h.changeAdminPassword(ctx, r, entityID)
+		response, err = h.changeAdminPassword(ctx, tx, r, entityID)
 	case "delete":
-		response, err = h.deleteAdmin(ctx, adminID, entityID)
+		response, err = h.deleteAdmin(ctx, tx, adminID, entityID)
 	default:
 		response = map[string]interface{}{"success": false, "message": util.T(ctx, "invalid_action_specified")}
 	}
@@ -391,28 +422,117 @@ func (h *AdminHandler) handlePost(w http.ResponseWriter, r *http.Request, adminI
 		return
 	}
 
+	if success, _ := response["success"].(bool); success {
+		if action == "create" {
+			entityID, _ = response["adminId"].(string)
+		}
+		if err := h.recordAudit(ctx, tx, r, adminID, action, entityID, before); err != nil {
+			http.Error(w, util.T(ctx, "failed_to_write_audit_trail", "Admin", err.Error()), http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *AdminHandler) createAdmin(ctx context.Context, r *http.Request, appAdminID string) (map[string]interface{}, error) {
+// adminAuditColumns are the columns of the admin table recorded in the audit trail.
+// The password is masked by the audit package.
+var adminAuditColumns = []string{"admin_id", "name", "username", "email", "password", "admin_level_id", "active"}
+
+// adminAuditActions maps the 'action' form value to the action recorded in the audit trail.
+var adminAuditActions = map[string]string{
+	"create":          audit.ActionCreate,
+	"update":          audit.ActionUpdate,
+	"toggle_active":   audit.ActionToggle,
+	"change_password": audit.ActionUpdate,
+	"delete":          audit.ActionDelete,
+}
+
+// recordAudit writes the audit entry of a successful action on an admin in the transaction of the action.
+func (h *AdminHandler) recordAudit(ctx context.Context, tx *sql.Tx, r *http.Request, appAdminID, action, entityID string, before audit.Snapshot) error {
+	auditAction, ok := adminAuditActions[action]
+	if !ok || entityID == "" {
+		return nil
+	}
+	var after audit.Snapshot
+	if auditAction != audit.ActionDelete {
+		after = audit.Load(ctx, tx, "admin", "admin_id", entityID, adminAuditColumns...)
+	}
+	return audit.Write(ctx, tx, audit.Entry{
+		Entity:   "admin",
+		RecordID: entityID,
+		Action:   auditAction,
+		Before:   before,
+		After:    after,
+		AdminID:  appAdminID,
+		IP:       util.GetClientIP(r),
+	})
+}
+
+// adminViewActions maps the 'view' query parameter to the permission required to display it.
+// Views that are not listed require the list permission.
+var adminViewActions = map[string]auth.Action{
+	"detail":          auth.ActionDetail,
+	"create":          auth.ActionCreate,
+	"edit":            auth.ActionUpdate,
+	"change-password": auth.ActionUpdate,
+}
+
+// adminPostActions maps the 'action' form value to the permission required to perform it.
+var adminPostActions = map[string]auth.Action{
+	"create":          auth.ActionCreate,
+	"update":          auth.ActionUpdate,
+	"toggle_active":   auth.ActionToggle,
+	"change_password": auth.ActionUpdate,
+	"delete":          auth.ActionDelete,
+}
+
+// authorize checks whether the logged-in admin may perform the action on the admin entity.
+// If not, it writes a forbidden response and returns false.
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request, appAdminID string, action auth.Action) bool {
+	ctx := r.Context()
+	if action == "" {
+		action = auth.ActionList
+	}
+	admin, err := auth.LoadAdmin(ctx, h.DB, appAdminID)
+	if err == nil && auth.IsAllowed(ctx, h.DB, admin, "admin", action) {
+		return true
+	}
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "forbidden")})
+	} else {
+		http.Error(w, util.T(ctx, "forbidden"), http.StatusForbidden)
+	}
+	return false
+}
+
+func (h *AdminHandler) createAdmin(ctx context.Context, tx *sql.Tx, r *http.Request, appAdminID string) (map[string]interface{}, error) {
 	password := r.FormValue("password")
 	if password == "" {
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "password_is_required")}, nil
 	}
-	hashedPassword := doubleSha1(password)
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return nil, errors.New(util.T(ctx, "failed_to_create_item", "Admin", err.Error()))
+	}
 	newID := generateUniqueID()
 	active := r.FormValue("active") == "on"
 
 	sql := `INSERT INTO admin (admin_id, name, username, email, password, admin_level_id, active, time_create, admin_create, ip_create) 
             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := h.DB.ExecContext(ctx, sql, newID, r.FormValue("name"), r.FormValue("username"), r.FormValue("email"), hashedPassword, r.FormValue("admin_level_id"), active, time.Now(), appAdminID, r.RemoteAddr)
+	_, err = tx.ExecContext(ctx, sql, newID, r.FormValue("name"), r.FormValue("username"), r.FormValue("email"), hashedPassword, r.FormValue("admin_level_id"), active, time.Now(), appAdminID, r.RemoteAddr)
 	if err != nil {
-		return nil, fmt.Errorf(util.T(ctx, "failed_to_create_item", "Admin", err.Error()))
+		return nil, errors.New(util.T(ctx, "failed_to_create_item", "Admin", err.Error()))
 	}
-	return map[string]interface{}{"success": true, "message": util.T(ctx, "admin_created_successfully")}, nil
+	return map[string]interface{}{"success": true, "message": util.T(ctx, "admin_created_successfully"), "adminId": newID}, nil
 }
 
-func (h *AdminHandler) updateAdmin(ctx context.Context, r *http.Request, appAdminID, entityID string) (map[string]interface{}, error) {
+func (h *AdminHandler) updateAdmin(ctx context.Context, tx *sql.Tx, r *http.Request, appAdminID, entityID string) (map[string]interface{}, error) {
 	if entityID == "" {
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "admin_id_required")}, nil
 	}
@@ -425,7 +545,7 @@ func (h *AdminHandler) updateAdmin(ctx context.Context, r *http.Request, appAdmi
 		active = true
 		// We need to fetch the current user's level to prevent change
 		var currentLevelID string
-		err := h.DB.QueryRowContext(ctx, "SELECT admin_level_id FROM admin WHERE admin_id = ?", appAdminID).Scan(&currentLevelID)
+		err := tx.QueryRowContext(ctx, "SELECT admin_level_id FROM admin WHERE admin_id = ?", appAdminID).Scan(&currentLevelID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch current admin level: %w", err)
 		}
@@ -434,14 +554,35 @@ func (h *AdminHandler) updateAdmin(ctx context.Context, r *http.Request, appAdmi
 
 	sql := `UPDATE admin SET name = ?, username = ?, email = ?, admin_level_id = ?, active = ?, 
             time_edit = ?, admin_edit = ?, ip_edit = ? WHERE admin_id = ?`
-	_, err := h.DB.ExecContext(ctx, sql, r.FormValue("name"), r.FormValue("username"), r.FormValue("email"), adminLevelID, active, time.Now(), appAdminID, r.RemoteAddr, entityID)
+	params := []interface{}{r.FormValue("name"), r.FormValue("username"), r.FormValue("email"), adminLevelID, active, time.Now(), appAdminID, r.RemoteAddr, entityID}
+
+	// The form sends the time_edit it was loaded with, so an admin changed by someone else in the meantime is not overwritten
+	_, checkVersion := r.Form["version"]
+	expectedVersion := r.FormValue("version")
+	if checkVersion {
+		condition, conditionParams := concurrency.Condition("time_edit", expectedVersion)
+		sql += " AND " + condition
+		params = append(params, conditionParams...)
+	}
+
+	result, err := tx.ExecContext(ctx, sql, params...)
 	if err != nil {
-		return nil, fmt.Errorf(util.T(ctx, "failed_to_update_item", "Admin", err.Error()))
+		return nil, errors.New(util.T(ctx, "failed_to_update_item", "Admin", err.Error()))
+	}
+	if checkVersion {
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			if err := concurrency.Verify(ctx, tx, "admin", "admin_id", entityID, "time_edit", expectedVersion); err != nil {
+				if apperror.CodeOf(err) == apperror.CodeConflict {
+					return map[string]interface{}{"success": false, "code": apperror.CodeConflict, "message": err.Error()}, nil
+				}
+				return nil, err
+			}
+		}
 	}
 	return map[string]interface{}{"success": true, "message": util.T(ctx, "admin_updated_successfully")}, nil
 }
 
-func (h *AdminHandler) toggleAdminActive(ctx context.Context, appAdminID, entityID string) (map[string]interface{}, error) {
+func (h *AdminHandler) toggleAdminActive(ctx context.Context, tx *sql.Tx, appAdminID, entityID string) (map[string]interface{}, error) {
 	if entityID == "" {
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "admin_id_required")}, nil
 	}
@@ -450,20 +591,20 @@ func (h *AdminHandler) toggleAdminActive(ctx context.Context, appAdminID, entity
 	}
 
 	var currentStatus bool
-	err := h.DB.QueryRowContext(ctx, "SELECT active FROM admin WHERE admin_id = ?", entityID).Scan(&currentStatus)
+	err := tx.QueryRowContext(ctx, "SELECT active FROM admin WHERE admin_id = ?", entityID).Scan(&currentStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch admin status: %w", err)
 	}
 
 	newStatus := !currentStatus
-	_, err = h.DB.ExecContext(ctx, "UPDATE admin SET active = ? WHERE admin_id = ?", newStatus, entityID)
+	_, err = tx.ExecContext(ctx, "UPDATE admin SET active = ? WHERE admin_id = ?", newStatus, entityID)
 	if err != nil {
-		return nil, fmt.Errorf(util.T(ctx, "failed_to_change_status", "admin", "active", err.Error()))
+		return nil, errors.New(util.T(ctx, "failed_to_change_status", "admin", "active", err.Error()))
 	}
 	return map[string]interface{}{"success": true, "message": util.T(ctx, "admin_status_updated")}, nil
 }
 
-func (h *AdminHandler) changeAdminPassword(ctx context.Context, r *http.Request, entityID string) (map[string]interface{}, error) {
+func (h *AdminHandler) changeAdminPassword(ctx context.Context, tx *sql.Tx, r *http.Request, entityID string) (map[string]interface{}, error) {
 	if entityID == "" {
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "admin_id_required")}, nil
 	}
@@ -472,15 +613,18 @@ func (h *AdminHandler) changeAdminPassword(ctx context.Context, r *http.Request,
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "password_is_required")}, nil
 	}
 
-	hashedPassword := doubleSha1(password)
-	_, err := h.DB.ExecContext(ctx, "UPDATE admin SET password = ? WHERE admin_id = ?", hashedPassword, entityID)
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return nil, errors.New(util.T(ctx, "failed_to_update_password"))
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE admin SET password = ? WHERE admin_id = ?", hashedPassword, entityID)
 	if err != nil {
-		return nil, fmt.Errorf(util.T(ctx, "failed_to_update_password"))
+		return nil, errors.New(util.T(ctx, "failed_to_update_password"))
 	}
 	return map[string]interface{}{"success": true, "message": util.T(ctx, "password_updated_successfully")}, nil
 }
 
-func (h *AdminHandler) deleteAdmin(ctx context.Context, appAdminID, entityID string) (map[string]interface{}, error) {
+func (h *AdminHandler) deleteAdmin(ctx context.Context, tx *sql.Tx, appAdminID, entityID string) (map[string]interface{}, error) {
 	if entityID == "" {
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "admin_id_required")}, nil
 	}
@@ -488,25 +632,20 @@ func (h *AdminHandler) deleteAdmin(ctx context.Context, appAdminID, entityID str
 		return map[string]interface{}{"success": false, "message": util.T(ctx, "cannot_delete_self")}, nil
 	}
 
-	_, err := h.DB.ExecContext(ctx, "DELETE FROM admin WHERE admin_id = ?", entityID)
+	// Keep a copy of the admin in the trash when it is enabled
+	if trash.Required() {
+		if err := trash.MoveRow(ctx, tx, "admin", "admin_id", entityID); err != nil {
+			return nil, errors.New(util.T(ctx, "failed_to_delete_item", "Admin", err.Error()))
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM admin WHERE admin_id = ?", entityID)
 	if err != nil {
-		return nil, fmt.Errorf(util.T(ctx, "failed_to_delete_item", "Admin", err.Error()))
+		return nil, errors.New(util.T(ctx, "failed_to_delete_item", "Admin", err.Error()))
 	}
 	return map[string]interface{}{"success": true, "message": util.T(ctx, "admin_deleted_successfully")}, nil
 }
 
-// doubleSha1 replicates the PHP sha1(sha1()) hashing.
-// Note: This is not a secure hashing method for production. Use bcrypt or scrypt.
-func doubleSha1(s string) string {
-	h1 := sha1.New()
-	h1.Write([]byte(s))
-	bs1 := h1.Sum(nil)
-
-	h2 := sha1.New()
-	h2.Write([]byte(fmt.Sprintf("%x", bs1)))
-	return fmt.Sprintf("%x", h2.Sum(nil))
-}
-
 // generateUniqueID creates a unique ID similar to PHP's uniqid().
 func generateUniqueID() string {
 	now := time.Now()
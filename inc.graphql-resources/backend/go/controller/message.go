@@ -7,6 +7,7 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"graphqlapplication/config"
 	"graphqlapplication/constant"
 	"graphqlapplication/systemmodel"
 	"graphqlapplication/util"
@@ -128,8 +129,8 @@ func (h *MessageHandler) getDetailMessage(w http.ResponseWriter, r *http.Request
 
 	// Attempt to mark the message as read.
 	// This is a "fire and forget" operation; we log an error but continue even if it fails.
-	_, err := h.DB.Exec("UPDATE message SET is_read = 1, time_read = ? WHERE message_id = ? AND receiver_id = ? AND (is_read = 0 OR is_read IS NULL)",
-		time.Now().Format(constant.DateTimeFormat), messageID, adminID)
+	_, err := h.DB.Exec("UPDATE message SET is_read = ?, time_read = ? WHERE message_id = ? AND receiver_id = ? AND (is_read = ? OR is_read IS NULL)",
+		true, time.Now().Format(constant.DateTimeFormat), messageID, adminID, false)
 	if err != nil {
 		log.Printf("Failed to mark message as read: %v", err) // Log error but continue
 	}
@@ -199,15 +200,16 @@ func (h *MessageHandler) getMessageList(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Fetch the actual message records for the current page.
+	pageSQL, pageParams := config.Dialect.Paginate("ORDER BY m.time_create DESC", pageSize, offset)
 	listQuery := `
 		SELECT m.message_id, m.subject, m.content, m.is_read, m.time_create, m.receiver_id,
 			sender.name AS sender_name, receiver.name AS receiver_name
 		FROM message m
 		LEFT JOIN admin receiver ON m.receiver_id = receiver.admin_id
 		LEFT JOIN admin sender ON m.sender_id = sender.admin_id ` +
-		whereClause + ` ORDER BY m.time_create DESC LIMIT ? OFFSET ?`
+		whereClause + ` ` + pageSQL
 
-	rows, err := h.DB.Query(listQuery, append(params, pageSize, offset)...)
+	rows, err := h.DB.Query(listQuery, append(params, pageParams...)...)
 	if err != nil {
 		http.Error(w, util.T(ctx, "failed_to_fetch_details"), http.StatusOK)
 		return
@@ -7,6 +7,7 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"graphqlapplication/config"
 	"graphqlapplication/constant"
 	"graphqlapplication/systemmodel"
 	"graphqlapplication/util"
@@ -141,8 +142,8 @@ func (h *NotificationHandler) getDetailNotification(w http.ResponseWriter, r *ht
 
 	// Attempt to mark the notification as read.
 	// This is a "fire and forget" operation; we log an error but continue even if it fails.
-	_, err := h.DB.Exec("UPDATE notification SET is_read = 1, time_read = ?, ip_read = ? WHERE notification_id = ? AND (admin_id = ? OR admin_group = ?) AND (is_read = 0 OR is_read IS NULL)",
-		time.Now().Format(constant.DateTimeFormat), util.GetClientIP(r), notificationID, adminID, adminLevelID)
+	_, err := h.DB.Exec("UPDATE notification SET is_read = ?, time_read = ?, ip_read = ? WHERE notification_id = ? AND (admin_id = ? OR admin_group = ?) AND (is_read = ? OR is_read IS NULL)",
+		true, time.Now().Format(constant.DateTimeFormat), util.GetClientIP(r), notificationID, adminID, adminLevelID, false)
 	if err != nil {
 		log.Printf("Failed to mark notification as read: %v", err) // Log error but continue
 	}
@@ -208,10 +209,11 @@ func (h *NotificationHandler) getNotificationList(w http.ResponseWriter, r *http
 	}
 
 	// Fetch the actual notification records for the current page.
+	pageSQL, pageParams := config.Dialect.Paginate("ORDER BY time_create DESC", pageSize, offset)
 	listQuery := `SELECT notification_id, subject, content, is_read, time_create, link
-            FROM notification ` + whereClause + ` ORDER BY time_create DESC LIMIT ? OFFSET ?`
+            FROM notification ` + whereClause + ` ` + pageSQL
 
-	rows, err := h.DB.Query(listQuery, append(params, pageSize, offset)...)
+	rows, err := h.DB.Query(listQuery, append(params, pageParams...)...)
 	if err != nil {
 		http.Error(w, util.T(ctx, "failed_to_fetch_details"), http.StatusOK)
 		return
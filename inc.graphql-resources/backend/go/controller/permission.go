@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"graphqlapplication/auth"
+	"graphqlapplication/constant"
+	"graphqlapplication/util"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/sessions"
+)
+
+// PermissionHandler handles the page used to grant entity permissions to admin levels.
+type PermissionHandler struct {
+	DB       *sql.DB
+	Store    *sessions.CookieStore
+	Entities []string
+}
+
+// NewPermissionHandler creates a new instance of PermissionHandler.
+// The entities are the names of the tables exposed by the GraphQL API.
+func NewPermissionHandler(db *sql.DB, store *sessions.CookieStore, entities []string) *PermissionHandler {
+	return &PermissionHandler{DB: db, Store: store, Entities: entities}
+}
+
+// PermissionPageData holds the data needed to render the permission template.
+type PermissionPageData struct {
+	AdminLevels  []AdminLevel
+	AdminLevelID string
+	Permissions  []*auth.Permission
+	Actions      []auth.Action
+}
+
+// systemEntities are the entities managed by the controllers rather than by the GraphQL API.
+var systemEntities = []string{"admin", "admin_level_permission"}
+
+// ServeHTTP is the main entry point for /admin-permission requests.
+func (h *PermissionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	lang := r.Header.Get("X-Language-Id")
+	if lang == "" {
+		lang = "en"
+	}
+	ctx = context.WithValue(ctx, constant.LanguageKey, lang)
+
+	session, err := h.Store.Get(r, constant.SessionKey)
+	if err != nil {
+		http.Error(w, util.T(ctx, "failed_to_get_session"), http.StatusInternalServerError)
+		return
+	}
+
+	adminID, ok := session.Values[constant.SessionAdminId].(string)
+	if !ok || adminID == "" {
+		http.Error(w, util.T(ctx, "forbidden"), http.StatusUnauthorized)
+		return
+	}
+
+	admin, err := auth.LoadAdmin(ctx, h.DB, adminID)
+	if err != nil {
+		http.Error(w, util.T(ctx, "forbidden"), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if !auth.IsAllowed(ctx, h.DB, admin, "admin_level_permission", auth.ActionUpdate) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "forbidden")})
+			return
+		}
+		h.handlePost(w, r.WithContext(ctx), adminID)
+	} else if r.Method == http.MethodGet {
+		if !auth.IsAllowed(ctx, h.DB, admin, "admin_level_permission", auth.ActionList) {
+			http.Error(w, util.T(ctx, "forbidden"), http.StatusForbidden)
+			return
+		}
+		h.handleGet(w, r.WithContext(ctx))
+	} else {
+		http.Error(w, util.T(ctx, "method_not_allowed"), http.StatusMethodNotAllowed)
+	}
+}
+
+// allEntities returns the system entities followed by the GraphQL entities.
+func (h *PermissionHandler) allEntities() []string {
+	return append(append([]string{}, systemEntities...), h.Entities...)
+}
+
+// handleGet displays the permission matrix of the selected admin level.
+func (h *PermissionHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	levelRows, err := h.DB.QueryContext(ctx, "SELECT admin_level_id, name FROM admin_level WHERE active = ? ORDER BY sort_order", true)
+	if err != nil {
+		http.Error(w, util.T(ctx, "failed_to_fetch_admin_levels", err.Error()), http.StatusOK)
+		return
+	}
+	defer levelRows.Close()
+
+	var adminLevels []AdminLevel
+	for levelRows.Next() {
+		var level AdminLevel
+		if err := levelRows.Scan(&level.ID, &level.Name); err != nil {
+			http.Error(w, util.T(ctx, "failed_to_scan_admin_levels", err.Error()), http.StatusOK)
+			return
+		}
+		adminLevels = append(adminLevels, level)
+	}
+
+	data := PermissionPageData{
+		AdminLevels:  adminLevels,
+		AdminLevelID: r.URL.Query().Get("adminLevelId"),
+		Actions:      auth.Actions,
+	}
+	if data.AdminLevelID == "" && len(adminLevels) > 0 {
+		data.AdminLevelID = adminLevels[0].ID
+	}
+
+	// Always read the current grants from the database rather than from the cache.
+	auth.InvalidatePermissions(data.AdminLevelID)
+	granted, err := auth.LoadPermissions(ctx, h.DB, data.AdminLevelID)
+	if err != nil {
+		http.Error(w, util.T(ctx, "database_error_details", err.Error()), http.StatusOK)
+		return
+	}
+	for _, entity := range h.allEntities() {
+		permission, ok := granted[entity]
+		if !ok {
+			permission = &auth.Permission{Entity: entity}
+		}
+		data.Permissions = append(data.Permissions, permission)
+	}
+
+	renderTemplate(w, r, "admin_permission.html", data)
+}
+
+// handlePost replaces all grants of an admin level with the submitted ones.
+// Each action is submitted as a multi-valued field named allowed_<action> containing entity names.
+func (h *PermissionHandler) handlePost(w http.ResponseWriter, r *http.Request, appAdminID string) {
+	ctx := r.Context()
+	w.Header().Set("Content-Type", "application/json")
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		http.Error(w, util.T(ctx, "failed_to_parse_form", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	adminLevelID := r.FormValue("adminLevelId")
+	if adminLevelID == "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "admin_level_id_required")})
+		return
+	}
+
+	permissions := make(map[string]*auth.Permission)
+	for _, entity := range h.allEntities() {
+		permissions[entity] = &auth.Permission{Entity: entity}
+	}
+	for _, action := range auth.Actions {
+		for _, entity := range r.MultipartForm.Value["allowed_"+string(action)] {
+			if permission, ok := permissions[entity]; ok {
+				permission.Set(action, true)
+			}
+		}
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "failed_to_update_item", "Permission", err.Error())})
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM admin_level_permission WHERE admin_level_id = ?", adminLevelID); err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "failed_to_update_item", "Permission", err.Error())})
+		return
+	}
+
+	query := `INSERT INTO admin_level_permission (admin_level_permission_id, admin_level_id, entity,
+			allowed_list, allowed_detail, allowed_create, allowed_update, allowed_delete, allowed_toggle,
+			time_create, admin_create, ip_create)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	now := time.Now().Format(constant.DateTimeFormat)
+	ip := util.GetClientIP(r)
+	for _, entity := range h.allEntities() {
+		p := permissions[entity]
+		_, err := tx.ExecContext(ctx, query, uuid.New().String(), adminLevelID, entity,
+			p.AllowedList, p.AllowedDetail, p.AllowedCreate, p.AllowedUpdate, p.AllowedDelete, p.AllowedToggle,
+			now, appAdminID, ip)
+		if err != nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "failed_to_update_item", "Permission", err.Error())})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "failed_to_update_item", "Permission", err.Error())})
+		return
+	}
+	auth.InvalidatePermissions(adminLevelID)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "message": util.T(ctx, "permissions_updated_successfully")})
+}
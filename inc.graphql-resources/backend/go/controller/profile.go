@@ -289,14 +289,19 @@ func (h *UserProfileHandler) handlePostUpdatePassword(w http.ResponseWriter, r *
 	}
 
 	// Verify that the provided current password matches the one stored in the database.
-	if util.DoubleSha1(currentPassword) != dbPassword {
+	if ok, _ := util.VerifyPassword(currentPassword, dbPassword); !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "incorrect_current_password")})
 		return
 	}
 
 	// Hash the new password before saving it.
-	newPasswordHash := util.DoubleSha1(newPassword)
+	newPasswordHash, err := util.HashPassword(newPassword)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "message": util.T(ctx, "failed_to_update_password")})
+		return
+	}
 
 	// Update the password and the 'last_reset_password' timestamp in the database.
 	_, err = h.DB.Exec(
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"strings"
+)
+
+// LikeEscape is the escape character used in LIKE patterns, as in "column LIKE ? ESCAPE '!'".
+// It is not a backslash because backslashes are handled differently by MySQL and PostgreSQL string literals.
+const LikeEscape = "!"
+
+// Dialect describes the SQL differences between the supported databases.
+// Queries are written with '?' placeholders; drivers that need other placeholders rebind them.
+type Dialect interface {
+	// Name returns the name of the dialect.
+	Name() string
+	// Rebind replaces the '?' placeholders of a query with the placeholders of the database.
+	Rebind(query string) string
+	// QuoteIdentifier quotes a table or column name.
+	QuoteIdentifier(name string) string
+	// EscapeLike escapes the wildcards of a value that is used in a LIKE pattern with LikeEscape.
+	EscapeLike(value string) string
+	// CaseInsensitiveLike returns the operator used for case-insensitive LIKE comparisons.
+	CaseInsensitiveLike() string
+	// Text converts a quoted column of any type to text, so it can be passed to LOWER and matched with LIKE.
+	Text(column string) string
+	// BoolLiteral returns the literal of a boolean value.
+	BoolLiteral(value bool) string
+	// Paginate appends the pagination clause to the ORDER BY clause and returns the parameters it uses.
+	Paginate(orderSQL string, limit, offset interface{}) (string, []interface{})
+	// InsertReturning builds an INSERT statement that returns the primary key of the new row.
+	// It returns false if the database does not support it; LastInsertId must be used instead.
+	InsertReturning(tableName, fields, placeholders, primaryKey string) (string, bool)
+}
+
+// DialectFor returns the dialect of a DB_DRIVER value. MySQL is used for unknown drivers.
+func DialectFor(driver string) Dialect {
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgsql":
+		return Postgres{}
+	case "sqlserver", "mssql", "sqlsrv":
+		return SQLServer{}
+	case "sqlite":
+		return SQLite{}
+	}
+	return MySQL{}
+}
+
+// likeReplacer escapes the LIKE wildcards and the escape character itself.
+var likeReplacer = strings.NewReplacer(LikeEscape, LikeEscape+LikeEscape, "%", LikeEscape+"%", "_", LikeEscape+"_")
+
+// limitOffset is the LIMIT/OFFSET pagination shared by MySQL, SQLite and PostgreSQL.
+func limitOffset(orderSQL string, limit, offset interface{}) (string, []interface{}) {
+	return strings.TrimSpace(orderSQL + " LIMIT ? OFFSET ?"), []interface{}{limit, offset}
+}
+
+// MySQL is the dialect of MySQL and MariaDB.
+type MySQL struct{}
+
+func (MySQL) Name() string                   { return "mysql" }
+func (MySQL) Rebind(query string) string     { return query }
+func (MySQL) EscapeLike(value string) string { return likeReplacer.Replace(value) }
+func (MySQL) CaseInsensitiveLike() string    { return "LIKE" }
+func (MySQL) Text(column string) string      { return column }
+
+func (MySQL) QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (MySQL) BoolLiteral(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (MySQL) Paginate(orderSQL string, limit, offset interface{}) (string, []interface{}) {
+	return limitOffset(orderSQL, limit, offset)
+}
+
+func (MySQL) InsertReturning(tableName, fields, placeholders, primaryKey string) (string, bool) {
+	return "", false
+}
+
+// SQLite is the dialect of SQLite.
+type SQLite struct{}
+
+func (SQLite) Name() string                   { return "sqlite" }
+func (SQLite) Rebind(query string) string     { return query }
+func (SQLite) EscapeLike(value string) string { return likeReplacer.Replace(value) }
+func (SQLite) CaseInsensitiveLike() string    { return "LIKE" }
+func (SQLite) Text(column string) string      { return column }
+
+func (SQLite) QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (SQLite) BoolLiteral(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func (SQLite) Paginate(orderSQL string, limit, offset interface{}) (string, []interface{}) {
+	return limitOffset(orderSQL, limit, offset)
+}
+
+func (SQLite) InsertReturning(tableName, fields, placeholders, primaryKey string) (string, bool) {
+	return "", false
+}
+
+// Postgres is the dialect of PostgreSQL.
+type Postgres struct{}
+
+func (Postgres) Name() string                   { return "postgres" }
+func (Postgres) Rebind(query string) string     { return rebind(query, "$") }
+func (Postgres) EscapeLike(value string) string { return likeReplacer.Replace(value) }
+func (Postgres) CaseInsensitiveLike() string    { return "ILIKE" }
+
+// Text casts the column, since PostgreSQL has no LOWER or LIKE for numbers, dates and booleans.
+func (Postgres) Text(column string) string {
+	return "CAST(" + column + " AS TEXT)"
+}
+
+func (Postgres) QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (Postgres) BoolLiteral(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (Postgres) Paginate(orderSQL string, limit, offset interface{}) (string, []interface{}) {
+	return limitOffset(orderSQL, limit, offset)
+}
+
+// InsertReturning uses RETURNING, since PostgreSQL does not support LastInsertId.
+func (Postgres) InsertReturning(tableName, fields, placeholders, primaryKey string) (string, bool) {
+	return "INSERT INTO " + tableName + " (" + fields + ") VALUES (" + placeholders + ") RETURNING " + primaryKey, true
+}
+
+// SQLServer is the dialect of Microsoft SQL Server.
+type SQLServer struct{}
+
+func (SQLServer) Name() string                { return "sqlserver" }
+func (SQLServer) Rebind(query string) string  { return rebind(query, "@p") }
+func (SQLServer) CaseInsensitiveLike() string { return "LIKE" }
+func (SQLServer) Text(column string) string   { return column }
+
+func (SQLServer) QuoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// EscapeLike also escapes '[', which starts a character range in SQL Server patterns.
+func (SQLServer) EscapeLike(value string) string {
+	return strings.ReplaceAll(likeReplacer.Replace(value), "[", LikeEscape+"[")
+}
+
+// BoolLiteral returns 1 or 0, since SQL Server has no boolean literals.
+func (SQLServer) BoolLiteral(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// Paginate uses OFFSET/FETCH, which requires an ORDER BY clause.
+func (SQLServer) Paginate(orderSQL string, limit, offset interface{}) (string, []interface{}) {
+	if strings.TrimSpace(orderSQL) == "" {
+		orderSQL = "ORDER BY (SELECT NULL)"
+	}
+	return orderSQL + " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{offset, limit}
+}
+
+// InsertReturning uses an OUTPUT clause, since SQL Server does not support LastInsertId.
+func (SQLServer) InsertReturning(tableName, fields, placeholders, primaryKey string) (string, bool) {
+	return "INSERT INTO " + tableName + " (" + fields + ") OUTPUT INSERTED." + primaryKey + " VALUES (" + placeholders + ")", true
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDialectFor(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{"mysql", "mysql"},
+		{"postgresql", "postgres"},
+		{"pgsql", "postgres"},
+		{"SQLServer", "sqlserver"},
+		{"mssql", "sqlserver"},
+		{"sqlsrv", "sqlserver"},
+		{"sqlite", "sqlite"},
+		{"unknown", "mysql"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			if got := DialectFor(tt.driver).Name(); got != tt.want {
+				t.Errorf("DialectFor(%q) = %q, want %q", tt.driver, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLServerDialect(t *testing.T) {
+	d := SQLServer{}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"rebind", d.Rebind("a = ? AND b = '?' AND c = ?"), "a = @p1 AND b = '?' AND c = @p2"},
+		{"quote identifier", d.QuoteIdentifier("a]b"), "[a]]b]"},
+		{"escape like", d.EscapeLike("[50%_!]"), "![50!%!_!!]"},
+		{"bool literal", d.BoolLiteral(false), "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		order   string
+		want    string
+		params  []interface{}
+	}{
+		{"MySQL", MySQL{}, "ORDER BY id", "ORDER BY id LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{"MySQL without ORDER BY", MySQL{}, "", "LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{"SQL Server", SQLServer{}, "ORDER BY id", "ORDER BY id OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{20, 10}},
+		{"SQL Server without ORDER BY", SQLServer{}, "", "ORDER BY (SELECT NULL) OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{20, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, params := tt.dialect.Paginate(tt.order, 10, 20)
+			if got != tt.want || !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("Paginate() = (%q, %v), want (%q, %v)", got, params, tt.want, tt.params)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// rebindDriver opens connections whose queries are rebound by the dialect before they reach the wrapped driver.
+type rebindDriver struct {
+	driver  driver.Driver
+	dialect Dialect
+}
+
+// Open opens a new connection with the wrapped driver.
+func (d *rebindDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &rebindConn{conn: conn, dialect: d.dialect}, nil
+}
+
+// rebindConn rebinds every query before passing it to the wrapped connection.
+// Optional interfaces of the wrapped connection are used when it implements them.
+type rebindConn struct {
+	conn    driver.Conn
+	dialect Dialect
+}
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.conn.Prepare(c.dialect.Rebind(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, c.dialect.Rebind(query))
+	}
+	return c.conn.Prepare(c.dialect.Rebind(query))
+}
+
+func (c *rebindConn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin is required by driver.Conn; database/sql uses BeginTx instead.
+func (c *rebindConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *rebindConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.conn.Begin()
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, c.dialect.Rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, c.dialect.Rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *rebindConn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *rebindConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *rebindConn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue lets the wrapped connection convert its own parameter types.
+func (c *rebindConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
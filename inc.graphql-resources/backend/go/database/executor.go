@@ -0,0 +1,14 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor runs statements. It is implemented by both *sql.DB and *sql.Tx,
+// so the same code can run inside or outside a transaction.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
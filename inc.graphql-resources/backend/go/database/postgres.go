@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/lib/pq"
+)
+
+// PostgresDriverName is the name of the PostgreSQL driver registered by this package.
+// It wraps github.com/lib/pq and rebinds the '?' placeholders used throughout the application,
+// so the same SQL runs on MySQL, SQLite and PostgreSQL.
+const PostgresDriverName = "postgres-rebind"
+
+func init() {
+	sql.Register(PostgresDriverName, &rebindDriver{driver: &pq.Driver{}, dialect: Postgres{}})
+}
+
+// PostgresDSN builds a PostgreSQL connection URL from the DB_* environment variables.
+// DB_SSL_MODE defaults to "disable"; DB_SCHEMA, if set, is used as the search path.
+func PostgresDSN() string {
+	sslMode := os.Getenv("DB_SSL_MODE")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	query := url.Values{}
+	query.Set("sslmode", sslMode)
+	if schema := os.Getenv("DB_SCHEMA"); schema != "" {
+		query.Set("search_path", schema)
+	}
+
+	host := os.Getenv("DB_HOST")
+	if port := os.Getenv("DB_PORT"); port != "" {
+		host = fmt.Sprintf("%s:%s", host, port)
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(os.Getenv("DB_USER"), os.Getenv("DB_PASS")),
+		Host:     host,
+		Path:     "/" + os.Getenv("DB_NAME"),
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
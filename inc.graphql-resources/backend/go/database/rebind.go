@@ -0,0 +1,67 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+)
+
+// rebind replaces the '?' placeholders of a query with numbered placeholders,
+// such as $1, $2, ... for PostgreSQL or @p1, @p2, ... for SQL Server.
+// Question marks inside string literals, quoted identifiers and comments are left unchanged.
+func rebind(query string, prefix string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// Copy the quoted text up to the closing quote; a doubled quote is an escaped quote.
+			end := i + 1
+			for end < len(query) {
+				if query[end] == c {
+					if end+1 < len(query) && query[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			sb.WriteString(query[i : end+1])
+			i = end
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			// Line comment
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				sb.WriteString(query[i:])
+				return sb.String()
+			}
+			sb.WriteString(query[i : i+end+1])
+			i += end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			// Block comment
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				sb.WriteString(query[i:])
+				return sb.String()
+			}
+			sb.WriteString(query[i : i+2+end+2])
+			i += 2 + end + 1
+		case c == '?':
+			n++
+			sb.WriteString(prefix)
+			sb.WriteString(strconv.Itoa(n))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package database
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", "SELECT 1", "SELECT 1"},
+		{"placeholders are numbered", "SELECT * FROM t WHERE a = ? AND b IN (?, ?)", "SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)"},
+		{"string literal", "SELECT '?' FROM t WHERE a = ?", "SELECT '?' FROM t WHERE a = $1"},
+		{"escaped quote in a string literal", "SELECT 'it''s ?' WHERE a = ?", "SELECT 'it''s ?' WHERE a = $1"},
+		{"quoted identifier", `SELECT "a?" FROM t WHERE b = ?`, `SELECT "a?" FROM t WHERE b = $1`},
+		{"line comment", "SELECT a -- why?\nFROM t WHERE b = ?", "SELECT a -- why?\nFROM t WHERE b = $1"},
+		{"line comment at the end", "SELECT ? -- why?", "SELECT $1 -- why?"},
+		{"block comment", "SELECT /* a? */ a FROM t WHERE b = ?", "SELECT /* a? */ a FROM t WHERE b = $1"},
+		{"unterminated string literal", "SELECT ? WHERE a = 'b?", "SELECT $1 WHERE a = 'b?"},
+		{"unterminated block comment", "SELECT ? /* a?", "SELECT $1 /* a?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(tt.query, "$"); got != tt.want {
+				t.Errorf("rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect(t *testing.T) {
+	d := Postgres{}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"rebind", d.Rebind("a = ? AND b = ?"), "a = $1 AND b = $2"},
+		{"quote identifier", d.QuoteIdentifier(`a"b`), `"a""b"`},
+		{"escape like", d.EscapeLike(`50%_!`), `50!%!_!!`},
+		{"case-insensitive like", d.CaseInsensitiveLike(), "ILIKE"},
+		{"text", d.Text(`"price"`), `CAST("price" AS TEXT)`},
+		{"bool literal", d.BoolLiteral(true), "TRUE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// SQLServerDriverName is the name of the SQL Server driver registered by this package.
+// It wraps github.com/microsoft/go-mssqldb and rebinds the '?' placeholders to @p1, @p2, ...
+const SQLServerDriverName = "sqlserver-rebind"
+
+func init() {
+	sql.Register(SQLServerDriverName, &rebindDriver{driver: &mssql.Driver{}, dialect: SQLServer{}})
+}
+
+// SQLServerDSN builds a SQL Server connection URL from the DB_* environment variables.
+// DB_SSL_MODE, if set, is passed as the encrypt option (e.g. "disable", "false", "true" or "strict").
+func SQLServerDSN() string {
+	query := url.Values{}
+	query.Set("database", os.Getenv("DB_NAME"))
+	if sslMode := os.Getenv("DB_SSL_MODE"); sslMode != "" {
+		query.Set("encrypt", sslMode)
+	}
+
+	host := os.Getenv("DB_HOST")
+	if port := os.Getenv("DB_PORT"); port != "" {
+		host = fmt.Sprintf("%s:%s", host, port)
+	}
+	dsn := url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(os.Getenv("DB_USER"), os.Getenv("DB_PASS")),
+		Host:     host,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"graphqlapplication/constant"
+)
+
+// Tx is a transaction started by BeginTx.
+// When it joins the transaction of the request, Commit and Rollback do nothing
+// and the transaction is committed or rolled back by the GraphQL handler instead.
+type Tx struct {
+	*sql.Tx
+	joined bool
+}
+
+// WithTx returns a copy of ctx that carries the transaction of a request.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, constant.Transaction, tx) // NOSONAR
+}
+
+// TxFromContext returns the transaction of the request, or nil if the request does not run in a transaction.
+func TxFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(constant.Transaction).(*sql.Tx)
+	return tx
+}
+
+// Conn returns the transaction of the request if there is one, or db otherwise.
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
+
+// BeginTx starts a transaction on db, or joins the transaction of the request if there is one.
+func BeginTx(ctx context.Context, db *sql.DB) (*Tx, error) {
+	if tx := TxFromContext(ctx); tx != nil {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// Commit commits the transaction unless it joined the transaction of the request.
+func (tx *Tx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls back the transaction unless it joined the transaction of the request.
+func (tx *Tx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
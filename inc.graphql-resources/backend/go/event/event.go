@@ -0,0 +1,126 @@
+package event
+
+import (
+	"context"
+	"graphqlapplication/constant"
+	"log"
+	"sync"
+)
+
+// Actions of the change events, as exposed by the ChangeAction enum.
+const (
+	ActionCreated = "CREATED"
+	ActionUpdated = "UPDATED"
+	ActionDeleted = "DELETED"
+)
+
+// bufferSize is the number of events a subscriber may fall behind before it loses events.
+const bufferSize = 64
+
+// Event is a change to a record, published by the mutations once the change is committed.
+type Event struct {
+	Entity string
+	Action string
+	ID     string
+	// Values holds the values of the columns of the record, indexed by column name:
+	// after the change, or before it for deleted records. Subscriptions match their filters against them.
+	Values map[string]interface{}
+	// Record is the resolver of the record that is sent to the subscribers.
+	Record interface{}
+}
+
+// Bus delivers the events published in this process to the subscribers of their entity.
+// Several instances of the application do not share their events.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+// NewBus creates a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string]map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel that receives the events of an entity until ctx is done, when it is closed.
+// A subscriber that does not keep up loses events rather than slowing down the mutations.
+func (b *Bus) Subscribe(ctx context.Context, entity string) <-chan Event {
+	events := make(chan Event, bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[entity] == nil {
+		b.subscribers[entity] = make(map[chan Event]struct{})
+	}
+	b.subscribers[entity][events] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers[entity], events)
+		if len(b.subscribers[entity]) == 0 {
+			delete(b.subscribers, entity)
+		}
+		close(events)
+		b.mu.Unlock()
+	}()
+	return events
+}
+
+// Publish sends an event to the subscribers of its entity without waiting for them.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for events := range b.subscribers[e.Entity] {
+		select {
+		case events <- e:
+		default:
+			log.Printf("Dropped the %s event of %s %s for a subscriber that is too slow", e.Action, e.Entity, e.ID)
+		}
+	}
+}
+
+// DefaultBus is the bus the generated resolvers publish to and subscribe on.
+var DefaultBus = NewBus()
+
+// Publish publishes events on DefaultBus.
+// In a request run in a single transaction (see WithPending), the events are held back until the transaction is committed.
+func Publish(ctx context.Context, events ...Event) {
+	if pending, ok := ctx.Value(constant.PendingEvents).(*Pending); ok {
+		pending.mu.Lock()
+		pending.events = append(pending.events, events...)
+		pending.mu.Unlock()
+		return
+	}
+	for _, e := range events {
+		DefaultBus.Publish(e)
+	}
+}
+
+// Subscribe subscribes to the events of an entity on DefaultBus until ctx is done.
+func Subscribe(ctx context.Context, entity string) <-chan Event {
+	return DefaultBus.Subscribe(ctx, entity)
+}
+
+// Pending holds the events published during a transaction.
+type Pending struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// WithPending returns a copy of ctx in which the published events are held back by the returned Pending.
+// Call Flush once the transaction is committed; if it is rolled back, the events are simply dropped.
+func WithPending(ctx context.Context) (context.Context, *Pending) {
+	pending := &Pending{}
+	return context.WithValue(ctx, constant.PendingEvents, pending), pending // NOSONAR
+}
+
+// Flush publishes the held events on DefaultBus.
+func (p *Pending) Flush() {
+	p.mu.Lock()
+	events := p.events
+	p.events = nil
+	p.mu.Unlock()
+	for _, e := range events {
+		DefaultBus.Publish(e)
+	}
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPendingEventsAreDeliveredAfterFlush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := Subscribe(ctx, "product")
+
+	txCtx, pending := WithPending(ctx)
+	Publish(txCtx, Event{Entity: "product", Action: ActionCreated, ID: "1"}, Event{Entity: "product", Action: ActionDeleted, ID: "2"})
+	select {
+	case e := <-events:
+		t.Fatalf("the %s event of %s was delivered before Flush", e.Action, e.ID)
+	default:
+	}
+
+	pending.Flush()
+	for _, want := range []string{"1", "2"} {
+		select {
+		case e := <-events:
+			if e.ID != want {
+				t.Errorf("received the event of %s, want %s", e.ID, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("the event of %s was not delivered after Flush", want)
+		}
+	}
+
+	// The events are published once.
+	pending.Flush()
+	select {
+	case e := <-events:
+		t.Errorf("the event of %s was delivered again", e.ID)
+	default:
+	}
+}
+
+func TestPublishWithoutPending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := Subscribe(ctx, "product")
+
+	Publish(ctx, Event{Entity: "category", ID: "1"}, Event{Entity: "product", ID: "2"})
+	select {
+	case e := <-events:
+		if e.ID != "2" {
+			t.Errorf("received the event of %s %s, want product 2", e.Entity, e.ID)
+		}
+	default:
+		t.Fatal("the event was not delivered")
+	}
+}
@@ -0,0 +1,439 @@
+/**
+ * A small GraphQL explorer: it runs the operations written in the editor against the endpoint of the
+ * application and browses the types of the schema. Queries and mutations are sent over HTTP with the
+ * session cookie; subscriptions use the graphql-transport-ws protocol over a WebSocket connection.
+ */
+class GraphQLExplorer {
+
+    /**
+     * @param {string} endpoint The path of the GraphQL endpoint.
+     */
+    constructor(endpoint) {
+        this.endpoint = endpoint;
+        this.socket = null;
+        this.schema = null;
+        this.types = {};
+        this.docsPath = [];
+
+        this.queryInput = document.getElementById('query');
+        this.variablesInput = document.getElementById('variables');
+        this.operationInput = document.getElementById('operation-name');
+        this.transactionInput = document.getElementById('transaction');
+        this.resultOutput = document.getElementById('result');
+        this.statusOutput = document.getElementById('status');
+        this.runButton = document.getElementById('run');
+        this.stopButton = document.getElementById('stop');
+
+        this.queryInput.value = localStorage.getItem('graphiql.query') || '{\n  __typename\n}\n';
+        this.variablesInput.value = localStorage.getItem('graphiql.variables') || '';
+        this.attachEvents();
+        this.loadSchema();
+    }
+
+    /**
+     * Attaches the listeners of the buttons and the editors.
+     */
+    attachEvents() {
+        this.runButton.addEventListener('click', () => this.run());
+        this.stopButton.addEventListener('click', () => this.stop());
+        document.getElementById('toggle-docs').addEventListener('click', () => {
+            document.getElementById('docs').classList.toggle('hidden');
+        });
+        [this.queryInput, this.variablesInput].forEach(input => {
+            input.addEventListener('keydown', event => this.onKeyDown(event));
+            input.addEventListener('input', () => {
+                localStorage.setItem('graphiql.query', this.queryInput.value);
+                localStorage.setItem('graphiql.variables', this.variablesInput.value);
+            });
+        });
+    }
+
+    /**
+     * Runs the operation on Ctrl+Enter and inserts two spaces on Tab.
+     * @param {KeyboardEvent} event
+     */
+    onKeyDown(event) {
+        if (event.key === 'Enter' && (event.ctrlKey || event.metaKey)) {
+            event.preventDefault();
+            this.run();
+        } else if (event.key === 'Tab' && !event.shiftKey) {
+            event.preventDefault();
+            const input = event.target;
+            const start = input.selectionStart;
+            input.value = input.value.substring(0, start) + '  ' + input.value.substring(input.selectionEnd);
+            input.selectionStart = input.selectionEnd = start + 2;
+        }
+    }
+
+    /**
+     * Reads the parameters of the request from the editors.
+     * @returns {{query: string, operationName: (string|undefined), variables: (Object|undefined)}|null}
+     */
+    readParams() {
+        let variables;
+        const text = this.variablesInput.value.trim();
+        if (text !== '') {
+            try {
+                variables = JSON.parse(text);
+            } catch (e) {
+                this.show('Invalid variables: ' + e.message, 'error');
+                return null;
+            }
+        }
+        return {
+            query: this.queryInput.value,
+            operationName: this.operationInput.value.trim() || undefined,
+            variables: variables
+        };
+    }
+
+    /**
+     * Runs the operation of the editor.
+     */
+    run() {
+        const params = this.readParams();
+        if (params === null) {
+            return;
+        }
+        this.stop();
+        if (this.isSubscription(params)) {
+            this.subscribe(params);
+        } else {
+            this.execute(params);
+        }
+    }
+
+    /**
+     * Tells whether the operation to run is a subscription.
+     * @param {{query: string, operationName: (string|undefined)}} params
+     * @returns {boolean}
+     */
+    isSubscription(params) {
+        const query = params.query.replace(/#[^\n\r]*/g, '');
+        if (params.operationName && /^[_A-Za-z][_0-9A-Za-z]*$/.test(params.operationName)) {
+            return new RegExp('\\bsubscription\\s+' + params.operationName + '\\b').test(query);
+        }
+        return /^\s*subscription\b/.test(query);
+    }
+
+    /**
+     * Sends a query or a mutation over HTTP and shows its result.
+     * @param {Object} params
+     */
+    execute(params) {
+        const headers = { 'Content-Type': 'application/json' };
+        if (this.transactionInput.checked) {
+            headers['X-Transaction'] = 'true';
+        }
+        const started = Date.now();
+        this.show('', 'running…');
+        fetch(this.endpoint, {
+            method: 'POST',
+            credentials: 'same-origin',
+            headers: headers,
+            body: JSON.stringify(params)
+        })
+            .then(response => response.text().then(text => ({ response, text })))
+            .then(({ response, text }) => {
+                let body = text;
+                try {
+                    body = JSON.stringify(JSON.parse(text), null, 2);
+                } catch (e) {
+                    // Not JSON, e.g. an HTTP error page
+                }
+                this.show(body, response.status + ' · ' + (Date.now() - started) + ' ms');
+            })
+            .catch(error => this.show(String(error), 'error'));
+    }
+
+    /**
+     * Runs a subscription over WebSocket and shows each of its results as they arrive.
+     * @param {Object} params
+     */
+    subscribe(params) {
+        const url = new URL(this.endpoint, window.location.href);
+        url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+        const socket = new WebSocket(url.href, 'graphql-transport-ws');
+        const results = [];
+        this.socket = socket;
+        this.runButton.disabled = true;
+        this.stopButton.disabled = false;
+        this.show('', 'connecting…');
+
+        socket.addEventListener('open', () => {
+            socket.send(JSON.stringify({ type: 'connection_init' }));
+        });
+        socket.addEventListener('message', event => {
+            const message = JSON.parse(event.data);
+            switch (message.type) {
+                case 'connection_ack':
+                    socket.send(JSON.stringify({ id: '1', type: 'subscribe', payload: params }));
+                    this.show('', 'subscribed, waiting for events…');
+                    break;
+                case 'ping':
+                    socket.send(JSON.stringify({ type: 'pong' }));
+                    break;
+                case 'next':
+                case 'error':
+                    results.unshift(message.type === 'next' ? message.payload : { errors: message.payload });
+                    this.show(results.map(result => JSON.stringify(result, null, 2)).join('\n\n'), results.length + ' result(s)');
+                    if (message.type === 'error') {
+                        // The operation ended without a complete message
+                        socket.close();
+                    }
+                    break;
+                case 'complete':
+                    socket.close();
+                    break;
+            }
+        });
+        socket.addEventListener('close', () => {
+            if (this.socket === socket) {
+                this.socket = null;
+                this.runButton.disabled = false;
+                this.stopButton.disabled = true;
+                this.statusOutput.textContent = results.length + ' result(s), closed';
+            }
+        });
+    }
+
+    /**
+     * Stops the running subscription, if any.
+     */
+    stop() {
+        if (this.socket !== null) {
+            const socket = this.socket;
+            if (socket.readyState === WebSocket.OPEN) {
+                socket.send(JSON.stringify({ id: '1', type: 'complete' }));
+            }
+            socket.close();
+        }
+    }
+
+    /**
+     * Shows a result and a status.
+     * @param {string} text
+     * @param {string} status
+     */
+    show(text, status) {
+        this.resultOutput.textContent = text;
+        this.statusOutput.textContent = status;
+    }
+
+    /**
+     * Fetches the schema by introspection and shows its root types in the documentation pane.
+     */
+    loadSchema() {
+        const query = 'query IntrospectionQuery { __schema { queryType { name } mutationType { name } subscriptionType { name } '
+            + 'types { kind name description '
+            + 'fields(includeDeprecated: true) { name description args { name description type { ...TypeRef } defaultValue } type { ...TypeRef } isDeprecated deprecationReason } '
+            + 'inputFields { name description type { ...TypeRef } defaultValue } '
+            + 'enumValues(includeDeprecated: true) { name description } } } } '
+            + 'fragment TypeRef on __Type { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } }';
+        fetch(this.endpoint, {
+            method: 'POST',
+            credentials: 'same-origin',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ query: query, operationName: 'IntrospectionQuery' })
+        })
+            .then(response => response.json())
+            .then(result => {
+                if (!result.data || !result.data.__schema) {
+                    this.showDocsMessage('The schema cannot be browsed: introspection is disabled for this account.');
+                    return;
+                }
+                this.schema = result.data.__schema;
+                this.schema.types.forEach(type => {
+                    this.types[type.name] = type;
+                });
+                this.showRoot();
+            })
+            .catch(error => this.showDocsMessage('Failed to load the schema: ' + error));
+    }
+
+    /**
+     * Shows a message instead of the documentation.
+     * @param {string} message
+     */
+    showDocsMessage(message) {
+        const content = document.getElementById('docs-content');
+        content.textContent = message;
+    }
+
+    /**
+     * Shows the root types of the schema and the other types, by kind.
+     */
+    showRoot() {
+        this.docsPath = [];
+        const content = this.clearDocs();
+        const roots = [this.schema.queryType, this.schema.mutationType, this.schema.subscriptionType].filter(type => type);
+        this.appendHeading(content, 'Root types');
+        roots.forEach(root => content.appendChild(this.entry([this.typeLink(root.name)])));
+
+        const kinds = { OBJECT: 'Types', INPUT_OBJECT: 'Input types', ENUM: 'Enums', SCALAR: 'Scalars' };
+        Object.keys(kinds).forEach(kind => {
+            const types = this.schema.types
+                .filter(type => type.kind === kind && !type.name.startsWith('__') && !roots.some(root => root.name === type.name))
+                .sort((a, b) => a.name.localeCompare(b.name));
+            if (types.length > 0) {
+                this.appendHeading(content, kinds[kind]);
+                types.forEach(type => content.appendChild(this.entry([this.typeLink(type.name)])));
+            }
+        });
+    }
+
+    /**
+     * Shows the fields, input fields or values of a type.
+     * @param {string} name
+     */
+    showType(name) {
+        const type = this.types[name];
+        if (!type) {
+            return;
+        }
+        if (this.docsPath[this.docsPath.length - 1] !== name) {
+            this.docsPath.push(name);
+        }
+        const content = this.clearDocs();
+        this.appendHeading(content, type.kind.toLowerCase().replace('_', ' ') + ' ' + type.name);
+        this.appendDescription(content, type.description);
+
+        (type.fields || type.inputFields || []).forEach(field => {
+            const parts = [this.span(field.name, 'field-name')];
+            if (field.args && field.args.length > 0) {
+                parts.push('(');
+                field.args.forEach((arg, index) => {
+                    parts.push((index > 0 ? ', ' : '') + arg.name + ': ');
+                    parts.push(this.typeRef(arg.type));
+                });
+                parts.push(')');
+            }
+            parts.push(': ');
+            parts.push(this.typeRef(field.type));
+            content.appendChild(this.entry(parts));
+            this.appendDescription(content, field.isDeprecated ? 'Deprecated: ' + (field.deprecationReason || '') : field.description);
+        });
+        (type.enumValues || []).forEach(value => {
+            content.appendChild(this.entry([this.span(value.name, 'field-name')]));
+            this.appendDescription(content, value.description);
+        });
+    }
+
+    /**
+     * Empties the documentation pane, shows the path to the current type and returns the content element.
+     * @returns {HTMLElement}
+     */
+    clearDocs() {
+        const nav = document.getElementById('docs-path');
+        nav.textContent = '';
+        const home = this.link('Schema', () => this.showRoot());
+        nav.appendChild(home);
+        this.docsPath.forEach((name, index) => {
+            nav.appendChild(document.createTextNode(' › '));
+            nav.appendChild(this.link(name, () => {
+                this.docsPath = this.docsPath.slice(0, index + 1);
+                this.showType(name);
+            }));
+        });
+        const content = document.getElementById('docs-content');
+        content.textContent = '';
+        return content;
+    }
+
+    /**
+     * Creates the element of a type reference such as [Product!]!, whose named type is a link.
+     * @param {Object} ref
+     * @returns {HTMLElement}
+     */
+    typeRef(ref) {
+        const span = document.createElement('span');
+        if (ref.kind === 'NON_NULL') {
+            span.appendChild(this.typeRef(ref.ofType));
+            span.appendChild(document.createTextNode('!'));
+        } else if (ref.kind === 'LIST') {
+            span.appendChild(document.createTextNode('['));
+            span.appendChild(this.typeRef(ref.ofType));
+            span.appendChild(document.createTextNode(']'));
+        } else {
+            span.appendChild(this.typeLink(ref.name));
+        }
+        return span;
+    }
+
+    /**
+     * Creates a link that shows a type.
+     * @param {string} name
+     * @returns {HTMLElement}
+     */
+    typeLink(name) {
+        return this.link(name, () => this.showType(name));
+    }
+
+    /**
+     * Creates a link that runs an action.
+     * @param {string} text
+     * @param {Function} action
+     * @returns {HTMLElement}
+     */
+    link(text, action) {
+        const a = document.createElement('a');
+        a.textContent = text;
+        a.addEventListener('click', action);
+        return a;
+    }
+
+    /**
+     * Creates a span with a class.
+     * @param {string} text
+     * @param {string} className
+     * @returns {HTMLElement}
+     */
+    span(text, className) {
+        const span = document.createElement('span');
+        span.className = className;
+        span.textContent = text;
+        return span;
+    }
+
+    /**
+     * Creates an entry of the documentation from text and elements.
+     * @param {Array<string|HTMLElement>} parts
+     * @returns {HTMLElement}
+     */
+    entry(parts) {
+        const div = document.createElement('div');
+        div.className = 'entry';
+        parts.forEach(part => div.appendChild(typeof part === 'string' ? document.createTextNode(part) : part));
+        return div;
+    }
+
+    /**
+     * Appends a heading to the documentation.
+     * @param {HTMLElement} content
+     * @param {string} text
+     */
+    appendHeading(content, text) {
+        const h3 = document.createElement('h3');
+        h3.textContent = text;
+        content.appendChild(h3);
+    }
+
+    /**
+     * Appends a description to the documentation, if there is one.
+     * @param {HTMLElement} content
+     * @param {string|null} text
+     */
+    appendDescription(content, text) {
+        if (text) {
+            const div = document.createElement('div');
+            div.className = 'description';
+            div.textContent = text;
+            content.appendChild(div);
+        }
+    }
+}
+
+document.addEventListener('DOMContentLoaded', () => {
+    const endpoint = document.querySelector('meta[name="graphql-endpoint"]').getAttribute('content') || '/graphql';
+    new GraphQLExplorer(endpoint);
+});
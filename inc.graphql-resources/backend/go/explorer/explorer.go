@@ -0,0 +1,69 @@
+package explorer
+
+import (
+	"embed"
+	"graphqlapplication/auth"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Path is the path the explorer is served at.
+const Path = "/graphiql"
+
+//go:embed assets
+var assets embed.FS
+
+// page is the template of the page of the explorer.
+var page = template.Must(template.ParseFS(assets, "assets/index.html"))
+
+// Enabled reports whether the explorer is served.
+// It is enabled by setting the GRAPHIQL_ENABLED environment variable to "true".
+func Enabled() bool {
+	return os.Getenv("GRAPHIQL_ENABLED") == "true"
+}
+
+// Handler serves an interactive explorer of the GraphQL API: an editor that runs queries, mutations and subscriptions,
+// and a browser of the types of the schema. Its page and assets are embedded in the application, so no CDN is needed.
+// Only logged-in admins may open it; the requests it sends are checked like any other request to the endpoint.
+type Handler struct {
+	// Endpoint is the path of the GraphQL endpoint.
+	Endpoint string
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if auth.AdminFromContext(r.Context()) == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; connect-src 'self' ws: wss:; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.URL.Path == Path || r.URL.Path == Path+"/" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, struct{ Endpoint string }{h.Endpoint}); err != nil {
+			log.Printf("Failed to render the GraphQL explorer: %v", err)
+		}
+		return
+	}
+
+	// The template of the page is not served as is
+	if strings.TrimPrefix(r.URL.Path, Path+"/") == "index.html" {
+		http.NotFound(w, r)
+		return
+	}
+	files.ServeHTTP(w, r)
+}
+
+// files serves the scripts and the styles of the explorer.
+var files = func() http.Handler {
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix(Path+"/", http.FileServer(http.FS(static)))
+}()
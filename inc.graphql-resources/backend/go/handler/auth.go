@@ -80,12 +80,18 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Compare sha1(sha1(password))
-	if util.DoubleSha1(password) != dbPassword {
+	// Verify the password against the stored hash (argon2id, bcrypt or legacy sha1(sha1(password)))
+	ok, needsRehash := util.VerifyPassword(password, dbPassword)
+	if !ok {
 		h.respondAuthError(w, "Invalid credentials")
 		return
 	}
 
+	// Silently upgrade legacy or outdated hashes
+	if needsRehash {
+		h.rehashPassword(dbAdminId, password)
+	}
+
 	// Success -> save session
 	session, _ := h.Store.Get(r, constant.SessionKey)
 	session.Values[constant.SessionUsername] = dbUsername
@@ -113,6 +119,20 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
+// rehashPassword replaces the stored password hash with one produced by the configured hasher.
+// Failures are only logged because the login itself has already succeeded.
+func (h *AuthHandler) rehashPassword(adminId, password string) {
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for admin %s: %v", adminId, err)
+		return
+	}
+	_, err = h.DB.Exec("UPDATE admin SET password = ? WHERE admin_id = ?", hashedPassword, adminId)
+	if err != nil {
+		log.Printf("Failed to store rehashed password for admin %s: %v", adminId, err)
+	}
+}
+
 func (h *AuthHandler) respondAuthError(w http.ResponseWriter, msg string) {
 	w.Header().Set("HTTP/1.1", "401 Unauthorized")
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
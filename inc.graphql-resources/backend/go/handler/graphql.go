@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"graphqlapplication/apperror"
+	"graphqlapplication/complexity"
+	"graphqlapplication/database"
+	"graphqlapplication/event"
+	"graphqlapplication/persisted"
+	"log"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// TransactionHeader is the request header that runs a GraphQL request in a single transaction.
+const TransactionHeader = "X-Transaction"
+
+// GraphQLHandler serves the GraphQL endpoint.
+// Requests sent with "X-Transaction: true" run in a single database transaction,
+// which is committed if no field returned an error and rolled back otherwise.
+// The change events of such a request are only published once the transaction is committed.
+// Resolver errors without a code are replaced with internal errors, so their details are only logged.
+// Requests may send the hash of a persisted query instead of the query, which is resolved by Persisted.
+// Requests that exceed the limits of the Analyzer are rejected before they run.
+type GraphQLHandler struct {
+	DB     *sql.DB
+	Schema *graphql.Schema
+	// TxSchema is the same schema parsed with graphql.MaxParallelism(1).
+	// Resolvers then run one at a time, so they can share the connection of the transaction.
+	TxSchema *graphql.Schema
+	// Analyzer computes the depth and the cost of the requests. It may be nil.
+	Analyzer *complexity.Analyzer
+	// Persisted resolves the persisted queries. It may be nil to disable them.
+	Persisted *persisted.Registry
+}
+
+// requestParams holds the parameters of a GraphQL request.
+type requestParams struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    struct {
+		PersistedQuery *persisted.Extension `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params requestParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if response := resolveQuery(ctx, h.Persisted, &params); response != nil {
+		h.writeResponse(w, response)
+		return
+	}
+	if response := checkComplexity(ctx, h.Analyzer, params.Query, params.OperationName, params.Variables); response != nil {
+		h.writeResponse(w, response)
+		return
+	}
+	if r.Header.Get(TransactionHeader) != "true" {
+		response := h.Schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+		h.writeResponse(w, safeResponse(ctx, response))
+		return
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		h.writeResponse(w, errorResponse(apperror.Internal(ctx, err, "transaction_failed")))
+		return
+	}
+
+	txCtx, pending := event.WithPending(database.WithTx(ctx, tx))
+	response := h.TxSchema.Exec(txCtx, params.Query, params.OperationName, params.Variables)
+	if len(response.Errors) > 0 {
+		if err := tx.Rollback(); err != nil {
+			log.Printf("Failed to roll back transaction: %v", err)
+		}
+	} else if err := tx.Commit(); err != nil {
+		response = errorResponse(apperror.Internal(ctx, err, "transaction_failed"))
+	} else {
+		pending.Flush()
+	}
+	h.writeResponse(w, safeResponse(ctx, response))
+}
+
+// resolveQuery replaces the query of a request with its persisted query.
+// It returns the response that rejects the request if its query cannot be resolved, or nil.
+func resolveQuery(ctx context.Context, registry *persisted.Registry, params *requestParams) *graphql.Response {
+	if registry == nil {
+		return nil
+	}
+	query, err := registry.Resolve(ctx, params.Query, params.Extensions.PersistedQuery)
+	if err != nil {
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) {
+			appErr = apperror.Internal(ctx, err, "internal_error")
+		}
+		return errorResponse(appErr)
+	}
+	params.Query = query
+	return nil
+}
+
+// checkComplexity computes the depth and the cost of a request and logs them with the correlation id of the request.
+// It returns the response that rejects the request if it exceeds the limits of the analyzer, or nil.
+func checkComplexity(ctx context.Context, analyzer *complexity.Analyzer, query string, operationName string, variables map[string]interface{}) *graphql.Response {
+	if analyzer == nil {
+		return nil
+	}
+	result, err := analyzer.Check(ctx, query, operationName, variables)
+	if err == nil {
+		log.Printf("[%s] GraphQL request depth %d, cost %d", apperror.CorrelationID(ctx), result.Depth, result.Cost)
+		return nil
+	}
+	log.Printf("[%s] GraphQL request rejected at depth %d, cost %d: %v", apperror.CorrelationID(ctx), result.Depth, result.Cost, err)
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		appErr = apperror.Internal(ctx, err, "internal_error")
+	}
+	return errorResponse(appErr)
+}
+
+// safeResponse replaces the resolver errors that have no code, such as database errors,
+// with internal errors. Their details are logged with the correlation id of the request.
+func safeResponse(ctx context.Context, response *graphql.Response) *graphql.Response {
+	for _, queryError := range response.Errors {
+		if queryError.ResolverError == nil || apperror.CodeOf(queryError.ResolverError) != "" {
+			continue
+		}
+		internal := apperror.Internal(ctx, queryError.ResolverError, "internal_error")
+		queryError.Message = internal.Error()
+		queryError.ResolverError = internal
+		queryError.Extensions = internal.Extensions()
+	}
+	return response
+}
+
+// errorResponse creates a response that only holds an error.
+func errorResponse(err *apperror.Error) *graphql.Response {
+	return &graphql.Response{Errors: []*gqlerrors.QueryError{{Message: err.Error(), ResolverError: err, Extensions: err.Extensions()}}}
+}
+
+// writeResponse writes a GraphQL response as JSON.
+func (h *GraphQLHandler) writeResponse(w http.ResponseWriter, response *graphql.Response) {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseJSON)
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"graphqlapplication/auth"
+	"graphqlapplication/complexity"
+	"graphqlapplication/persisted"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"golang.org/x/net/websocket"
+)
+
+// SubscriptionProtocol is the WebSocket subprotocol served by SubscriptionHandler.
+const SubscriptionProtocol = "graphql-transport-ws"
+
+// connectionInitTimeout is how long a client may wait before sending connection_init.
+const connectionInitTimeout = 10 * time.Second
+
+// Message types of the graphql-transport-ws protocol.
+const (
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+)
+
+// SubscriptionHandler serves GraphQL subscriptions over WebSocket with the graphql-transport-ws protocol.
+// Queries and mutations sent over the connection are answered with a single result.
+// Only logged-in admins may connect: the session cookie is checked during the handshake, and
+// browsers may only connect from the origin of the application.
+// Each operation is checked against the limits of the Analyzer before it runs.
+type SubscriptionHandler struct {
+	Schema *graphql.Schema
+	// Analyzer computes the depth and the cost of the operations. It may be nil.
+	Analyzer *complexity.Analyzer
+	// Persisted resolves the persisted queries. It may be nil to disable them.
+	Persisted *persisted.Registry
+}
+
+// IsWebSocket tells whether a request asks to upgrade the connection to WebSocket.
+func IsWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+func (h *SubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if auth.AdminFromContext(r.Context()) == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	server := websocket.Server{Handshake: h.handshake, Handler: h.serve}
+	server.ServeHTTP(w, r)
+}
+
+// handshake accepts connections that use the graphql-transport-ws subprotocol and come from the same origin.
+func (h *SubscriptionHandler) handshake(config *websocket.Config, r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return errors.New("cross-origin WebSocket connection refused")
+		}
+	}
+	for _, protocol := range config.Protocol {
+		if protocol == SubscriptionProtocol {
+			config.Protocol = []string{SubscriptionProtocol}
+			return nil
+		}
+	}
+	return errors.New("unsupported WebSocket subprotocol")
+}
+
+// wsMessage is a message of the graphql-transport-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsConnection holds the operations running on a WebSocket connection.
+type wsConnection struct {
+	ws         *websocket.Conn
+	schema     *graphql.Schema
+	analyzer   *complexity.Analyzer
+	persisted  *persisted.Registry
+	mu         sync.Mutex
+	operations map[string]context.CancelFunc
+}
+
+// serve runs a WebSocket connection until the client closes it or breaks the protocol.
+func (h *SubscriptionHandler) serve(ws *websocket.Conn) {
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+	defer ws.Close()
+
+	conn := &wsConnection{ws: ws, schema: h.Schema, analyzer: h.Analyzer, persisted: h.Persisted, operations: make(map[string]context.CancelFunc)}
+	initialized := false
+	ws.SetReadDeadline(time.Now().Add(connectionInitTimeout))
+	for {
+		var data string
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			if err != io.EOF {
+				log.Printf("WebSocket connection closed: %v", err)
+			}
+			return
+		}
+		var msg wsMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			log.Printf("Invalid WebSocket message: %v", err)
+			return
+		}
+
+		switch msg.Type {
+		case messageConnectionInit:
+			if initialized {
+				return
+			}
+			initialized = true
+			ws.SetReadDeadline(time.Time{})
+			conn.send(wsMessage{Type: messageConnectionAck})
+		case messagePing:
+			conn.send(wsMessage{Type: messagePong})
+		case messagePong:
+		case messageSubscribe:
+			if !initialized || msg.ID == "" || !conn.start(ctx, msg) {
+				return
+			}
+		case messageComplete:
+			conn.finish(msg.ID)
+		default:
+			log.Printf("Unknown WebSocket message type %q", msg.Type)
+			return
+		}
+	}
+}
+
+// start runs an operation. It returns false if an operation with the same id is already running.
+func (c *wsConnection) start(ctx context.Context, msg wsMessage) bool {
+	var params requestParams
+	if err := json.Unmarshal(msg.Payload, &params); err != nil {
+		log.Printf("Invalid subscribe payload: %v", err)
+		return false
+	}
+
+	c.mu.Lock()
+	if _, running := c.operations[msg.ID]; running {
+		c.mu.Unlock()
+		return false
+	}
+	opCtx, cancel := context.WithCancel(ctx)
+	c.operations[msg.ID] = cancel
+	c.mu.Unlock()
+
+	response := resolveQuery(opCtx, c.persisted, &params)
+	if response == nil {
+		response = checkComplexity(opCtx, c.analyzer, params.Query, params.OperationName, params.Variables)
+	}
+	if response != nil {
+		c.finish(msg.ID)
+		c.sendErrors(msg.ID, response.Errors)
+		return true
+	}
+	responses, err := c.schema.Subscribe(opCtx, params.Query, params.OperationName, params.Variables)
+	if err != nil {
+		c.finish(msg.ID)
+		c.sendErrors(msg.ID, []*gqlerrors.QueryError{{Message: err.Error()}})
+		return true
+	}
+
+	go func() {
+		for item := range responses {
+			response, ok := item.(*graphql.Response)
+			if !ok {
+				continue
+			}
+			response = safeResponse(opCtx, response)
+			if response.Data == nil && len(response.Errors) > 0 {
+				// The operation could not be run, e.g. because it is not valid
+				if c.finish(msg.ID) {
+					c.sendErrors(msg.ID, response.Errors)
+				}
+				return
+			}
+			payload, err := json.Marshal(response)
+			if err != nil {
+				log.Printf("Failed to encode the result of operation %s: %v", msg.ID, err)
+				continue
+			}
+			c.send(wsMessage{ID: msg.ID, Type: messageNext, Payload: payload})
+		}
+		// The client is only told that the operation is complete if it did not stop it or close the connection
+		if opCtx.Err() == nil && c.finish(msg.ID) {
+			c.send(wsMessage{ID: msg.ID, Type: messageComplete})
+		}
+	}()
+	return true
+}
+
+// finish cancels an operation and forgets it. It returns false if the operation was not running.
+func (c *wsConnection) finish(id string) bool {
+	c.mu.Lock()
+	cancel, running := c.operations[id]
+	delete(c.operations, id)
+	c.mu.Unlock()
+	if running {
+		cancel()
+	}
+	return running
+}
+
+// sendErrors sends the errors of an operation that could not be run.
+func (c *wsConnection) sendErrors(id string, errs []*gqlerrors.QueryError) {
+	payload, err := json.Marshal(errs)
+	if err != nil {
+		log.Printf("Failed to encode the errors of operation %s: %v", id, err)
+		return
+	}
+	c.send(wsMessage{ID: id, Type: messageError, Payload: payload})
+}
+
+// send writes a message to the client. Write errors end the connection through the read loop.
+func (c *wsConnection) send(msg wsMessage) {
+	if err := websocket.JSON.Send(c.ws, msg); err != nil {
+		log.Printf("Failed to send a WebSocket message: %v", err)
+	}
+}
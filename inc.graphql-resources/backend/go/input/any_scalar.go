@@ -1,7 +1,7 @@
 package input
 
 import (
-	"fmt"
+	"encoding/json"
 )
 
 // Any is a custom scalar type that can represent any value.
@@ -18,11 +18,17 @@ func (a *Any) UnmarshalGraphQL(input interface{}) error {
 }
 
 // MarshalJSON is called when sending a value to a client.
+// Numbers, booleans, null, lists and objects are sent as such, not as strings.
 func (a Any) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf("%q", a.v)), nil
+	return json.Marshal(a.v)
 }
 
 // Value returns the underlying value of the Any scalar.
 func (a *Any) Value() interface{} {
 	return a.v
-}
\ No newline at end of file
+}
+
+// NewAny creates an Any scalar holding a value, e.g. to send it to a client.
+func NewAny(v interface{}) Any {
+	return Any{v: v}
+}
@@ -0,0 +1,50 @@
+package input
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+)
+
+// BuildBulkFilter builds the WHERE clause that selects the rows of a bulk update or delete.
+// An empty filter would affect every row of the table, so it is refused unless force is true.
+func BuildBulkFilter(ctx context.Context, filter *[]*FilterInput, force *bool, columns ColumnMap, dialect database.Dialect) (string, []interface{}, error) {
+	whereSQL, _, params, err := BuildQuery(ctx, filter, nil, columns, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	if whereSQL == "" && (force == nil || !*force) {
+		return "", nil, apperror.Validation(util.T(ctx, "filter_required"))
+	}
+	return whereSQL, params, nil
+}
+
+// ExecForKeys completes an UPDATE or DELETE statement with a "WHERE column IN (...)" clause and runs it on the rows of keys.
+// The keys are sent in batches of batchSize, so the number of placeholders stays within the limits of the databases.
+// params are the parameters of the statement itself, such as the values set by an UPDATE.
+// It returns the number of rows the statement changed.
+func ExecForKeys[K any](ctx context.Context, db database.Executor, statement string, params []interface{}, column string, keys []K, batchSize int) (int64, error) {
+	if batchSize < 1 {
+		batchSize = len(keys)
+	}
+	var affected int64
+	for start := 0; start < len(keys); start += batchSize {
+		end := min(start+batchSize, len(keys))
+		values := make([]interface{}, 0, end-start)
+		for _, key := range keys[start:end] {
+			values = append(values, key)
+		}
+		whereSQL, batchParams := WhereIn("", params, column, values)
+		result, err := db.ExecContext(ctx, statement+" "+whereSQL, batchParams...)
+		if err != nil {
+			return affected, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return affected, err
+		}
+		affected += n
+	}
+	return affected, nil
+}
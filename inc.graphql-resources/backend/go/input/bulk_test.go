@@ -0,0 +1,103 @@
+package input
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// statement is a statement run by recordingExecutor.
+type statement struct {
+	query  string
+	params []interface{}
+}
+
+// recordingExecutor records the statements it runs and reports every parameter after the first skip as an affected row.
+type recordingExecutor struct {
+	skip       int
+	statements []statement
+}
+
+func (e *recordingExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.statements = append(e.statements, statement{query, args})
+	return driverResult(len(args) - e.skip), nil
+}
+
+func (e *recordingExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (e *recordingExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+// driverResult is the result of a statement that changed a given number of rows.
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestExecForKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		statement  string
+		params     []interface{}
+		keys       []string
+		batchSize  int
+		want       []statement
+		wantAffect int64
+	}{
+		{
+			name:      "delete in one batch",
+			statement: "DELETE FROM t",
+			keys:      []string{"a", "b"},
+			batchSize: 10,
+			want: []statement{
+				{"DELETE FROM t WHERE id IN (?, ?)", []interface{}{"a", "b"}},
+			},
+			wantAffect: 2,
+		},
+		{
+			name:      "update in several batches",
+			statement: "UPDATE t SET `name` = ?",
+			params:    []interface{}{"x"},
+			keys:      []string{"a", "b", "c"},
+			batchSize: 2,
+			want: []statement{
+				{"UPDATE t SET `name` = ? WHERE id IN (?, ?)", []interface{}{"x", "a", "b"}},
+				{"UPDATE t SET `name` = ? WHERE id IN (?)", []interface{}{"x", "c"}},
+			},
+			wantAffect: 3,
+		},
+		{
+			name:      "batch size that is not set",
+			statement: "DELETE FROM t",
+			keys:      []string{"a", "b", "c"},
+			want: []statement{
+				{"DELETE FROM t WHERE id IN (?, ?, ?)", []interface{}{"a", "b", "c"}},
+			},
+			wantAffect: 3,
+		},
+		{
+			name:      "no keys",
+			statement: "DELETE FROM t",
+			batchSize: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &recordingExecutor{skip: len(tt.params)}
+			affected, err := ExecForKeys(context.Background(), db, tt.statement, tt.params, "id", tt.keys, tt.batchSize)
+			if err != nil {
+				t.Fatalf("ExecForKeys() failed: %v", err)
+			}
+			if !reflect.DeepEqual(db.statements, tt.want) {
+				t.Errorf("ExecForKeys() ran %v, want %v", db.statements, tt.want)
+			}
+			if affected != tt.wantAffect {
+				t.Errorf("ExecForKeys() = %d, want %d", affected, tt.wantAffect)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package input
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+	"strings"
+)
+
+// DefaultConnectionSize is the number of edges returned when neither first nor last is given.
+const DefaultConnectionSize int32 = 10
+
+// ConnectionArgs holds the arguments of Relay-style connection queries.
+type ConnectionArgs struct {
+	First   *int32
+	After   *string
+	Last    *int32
+	Before  *string
+	OrderBy *[]*SortInput
+	Filter  *[]*FilterInput
+}
+
+// SortKey is a column a connection is sorted by.
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// ConnectionQuery holds the clauses of a connection query built by BuildConnectionQuery.
+// Where and Params only contain the filters and are used to count the matching rows;
+// PageWhere and PageParams also contain the conditions of the after and before cursors.
+type ConnectionQuery struct {
+	Where      string
+	Params     []interface{}
+	PageWhere  string
+	PageParams []interface{}
+	Order      string
+	Keys       []SortKey
+	Size       int32
+	Backward   bool
+	HasAfter   bool
+	HasBefore  bool
+}
+
+// BuildConnectionQuery builds a keyset-paginated query from the arguments of a connection.
+// The rows are sorted by the orderBy fields followed by the primary key, so every row has a unique position.
+// Cursors hold the values of these sort keys, and the rows after or before a cursor are selected by comparing them.
+// When last is given without first, the rows are fetched in reverse order and must be reversed by the caller.
+func BuildConnectionQuery(ctx context.Context, args ConnectionArgs, columns ColumnMap, primaryKey string, dialect database.Dialect) (ConnectionQuery, error) {
+	whereSQL, _, params, err := BuildQuery(ctx, args.Filter, nil, columns, dialect)
+	if err != nil {
+		return ConnectionQuery{}, err
+	}
+	query := ConnectionQuery{
+		Where:     whereSQL,
+		Params:    params,
+		Size:      DefaultConnectionSize,
+		HasAfter:  args.After != nil,
+		HasBefore: args.Before != nil,
+	}
+
+	// Sort keys
+	hasPrimaryKey := false
+	if args.OrderBy != nil {
+		for _, s := range *args.OrderBy {
+			if s == nil || s.Field == "" {
+				continue
+			}
+			column, ok := columns.Column(s.Field)
+			if !ok {
+				return ConnectionQuery{}, apperror.Validation(util.T(ctx, "invalid_sort_field", s.Field))
+			}
+			descending := s.Direction != nil && strings.ToUpper(*s.Direction) == "DESC"
+			query.Keys = append(query.Keys, SortKey{Column: column, Descending: descending})
+			if column == primaryKey {
+				hasPrimaryKey = true
+			}
+		}
+	}
+	if !hasPrimaryKey {
+		query.Keys = append(query.Keys, SortKey{Column: primaryKey})
+	}
+
+	// Page size and direction
+	if args.First != nil {
+		query.Size = *args.First
+	} else if args.Last != nil {
+		query.Size = *args.Last
+		query.Backward = true
+	}
+	if query.Size < 0 {
+		return ConnectionQuery{}, apperror.Validation(util.T(ctx, "invalid_page_size"))
+	}
+	if MaxPageSize > 0 && query.Size > MaxPageSize {
+		query.Size = MaxPageSize
+	}
+
+	// Cursor conditions
+	var conditions []string
+	pageParams := append([]interface{}{}, params...)
+	if args.After != nil {
+		condition, cursorParams, err := query.cursorCondition(ctx, *args.After, false, dialect)
+		if err != nil {
+			return ConnectionQuery{}, err
+		}
+		conditions = append(conditions, condition)
+		pageParams = append(pageParams, cursorParams...)
+	}
+	if args.Before != nil {
+		condition, cursorParams, err := query.cursorCondition(ctx, *args.Before, true, dialect)
+		if err != nil {
+			return ConnectionQuery{}, err
+		}
+		conditions = append(conditions, condition)
+		pageParams = append(pageParams, cursorParams...)
+	}
+	if whereSQL != "" {
+		conditions = append([]string{strings.TrimPrefix(whereSQL, "WHERE ")}, conditions...)
+	}
+	if len(conditions) > 0 {
+		query.PageWhere = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query.PageParams = pageParams
+
+	// Order, reversed when fetching backwards
+	orderClauses := make([]string, len(query.Keys))
+	for i, key := range query.Keys {
+		dir := "ASC"
+		if key.Descending != query.Backward {
+			dir = "DESC"
+		}
+		orderClauses[i] = fmt.Sprintf("%s %s", dialect.QuoteIdentifier(key.Column), dir)
+	}
+	query.Order = "ORDER BY " + strings.Join(orderClauses, ", ")
+
+	return query, nil
+}
+
+// cursorCondition builds the condition that selects the rows after a cursor, or before it if before is true.
+// For sort keys (a, b) it produces "(a > ?) OR (a = ? AND b > ?)", with "<" for descending keys.
+// A NULL cursor value only matches NULL for equality and is skipped for comparison,
+// so connections should be sorted by columns that are not null.
+func (q ConnectionQuery) cursorCondition(ctx context.Context, cursor string, before bool, dialect database.Dialect) (string, []interface{}, error) {
+	values, err := decodeCursor(cursor)
+	if err != nil || len(values) != len(q.Keys) {
+		return "", nil, apperror.Validation(util.T(ctx, "invalid_cursor"))
+	}
+
+	var terms []string
+	var params []interface{}
+	for i, key := range q.Keys {
+		if values[i] == nil {
+			continue
+		}
+		var parts []string
+		var termParams []interface{}
+		for j := 0; j < i; j++ {
+			column := dialect.QuoteIdentifier(q.Keys[j].Column)
+			if values[j] == nil {
+				parts = append(parts, column+" IS NULL")
+			} else {
+				parts = append(parts, column+" = ?")
+				termParams = append(termParams, values[j])
+			}
+		}
+		op := ">"
+		if key.Descending != before {
+			op = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", dialect.QuoteIdentifier(key.Column), op))
+		termParams = append(termParams, values[i])
+
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		params = append(params, termParams...)
+	}
+	if len(terms) == 0 {
+		return "1 = 0", nil, nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", params, nil
+}
+
+// Cursor builds the opaque cursor of a row from the values of its sort keys.
+// value returns the value of a column of the row.
+func (q ConnectionQuery) Cursor(value func(column string) interface{}) string {
+	values := make([]interface{}, len(q.Keys))
+	for i, key := range q.Keys {
+		values[i] = value(key.Column)
+	}
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// PageInfo returns whether there are pages after and before the fetched page.
+// hasMore tells whether more rows than the page size were fetched in the direction of the query.
+// In the other direction, a page is assumed to exist when a cursor was given.
+func (q ConnectionQuery) PageInfo(hasMore bool) (hasNext bool, hasPrevious bool) {
+	if q.Backward {
+		return q.HasBefore, hasMore
+	}
+	return hasMore, q.HasAfter
+}
+
+// decodeCursor returns the sort key values held by a cursor.
+// Numbers are returned as strings, so large integers keep their precision.
+func decodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		if n, ok := v.(json.Number); ok {
+			values[i] = n.String()
+		}
+	}
+	return values, nil
+}
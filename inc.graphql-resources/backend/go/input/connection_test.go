@@ -0,0 +1,154 @@
+package input
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"reflect"
+	"testing"
+)
+
+// cursorOf builds the cursor of a row of a connection sorted by keys.
+func cursorOf(keys []SortKey, row map[string]interface{}) string {
+	return ConnectionQuery{Keys: keys}.Cursor(func(column string) interface{} { return row[column] })
+}
+
+func TestBuildConnectionQuery(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+	byID := []SortKey{{Column: "id"}}
+	byNameDesc := []SortKey{{Column: "name", Descending: true}, {Column: "id"}}
+	nameDesc := &[]*SortInput{{Field: "name", Direction: strPtr("DESC")}}
+
+	tests := []struct {
+		name       string
+		args       ConnectionArgs
+		pageWhere  string
+		pageParams []interface{}
+		order      string
+		size       int32
+		backward   bool
+	}{
+		{
+			name:       "first page sorted by the primary key",
+			args:       ConnectionArgs{},
+			pageParams: []interface{}{},
+			order:      "ORDER BY `id` ASC",
+			size:       DefaultConnectionSize,
+		},
+		{
+			name:       "after a cursor",
+			args:       ConnectionArgs{First: int32Ptr(2), After: strPtr(cursorOf(byID, map[string]interface{}{"id": 5}))},
+			pageWhere:  "WHERE ((`id` > ?))",
+			pageParams: []interface{}{"5"},
+			order:      "ORDER BY `id` ASC",
+			size:       2,
+		},
+		{
+			name:       "large integers keep their precision",
+			args:       ConnectionArgs{After: strPtr(cursorOf(byID, map[string]interface{}{"id": int64(9007199254740993)}))},
+			pageWhere:  "WHERE ((`id` > ?))",
+			pageParams: []interface{}{"9007199254740993"},
+			order:      "ORDER BY `id` ASC",
+			size:       DefaultConnectionSize,
+		},
+		{
+			name:       "after a cursor sorted by a descending field",
+			args:       ConnectionArgs{OrderBy: nameDesc, After: strPtr(cursorOf(byNameDesc, map[string]interface{}{"name": "b", "id": 7}))},
+			pageWhere:  "WHERE ((`name` < ?) OR (`name` = ? AND `id` > ?))",
+			pageParams: []interface{}{"b", "b", "7"},
+			order:      "ORDER BY `name` DESC, `id` ASC",
+			size:       DefaultConnectionSize,
+		},
+		{
+			name:       "last before a cursor is fetched in reverse order",
+			args:       ConnectionArgs{Last: int32Ptr(3), OrderBy: nameDesc, Before: strPtr(cursorOf(byNameDesc, map[string]interface{}{"name": "b", "id": 7}))},
+			pageWhere:  "WHERE ((`name` > ?) OR (`name` = ? AND `id` < ?))",
+			pageParams: []interface{}{"b", "b", "7"},
+			order:      "ORDER BY `name` ASC, `id` DESC",
+			size:       3,
+			backward:   true,
+		},
+		{
+			name:       "null cursor values only match null",
+			args:       ConnectionArgs{OrderBy: nameDesc, After: strPtr(cursorOf(byNameDesc, map[string]interface{}{"name": nil, "id": 7}))},
+			pageWhere:  "WHERE ((`name` IS NULL AND `id` > ?))",
+			pageParams: []interface{}{"7"},
+			order:      "ORDER BY `name` DESC, `id` ASC",
+			size:       DefaultConnectionSize,
+		},
+		{
+			name:       "filters are combined with the cursor",
+			args:       ConnectionArgs{Filter: &[]*FilterInput{{Field: strPtr("name"), Value: anyPtr("a")}}, After: strPtr(cursorOf(byID, map[string]interface{}{"id": 5}))},
+			pageWhere:  "WHERE `name` = ? AND ((`id` > ?))",
+			pageParams: []interface{}{"a", "5"},
+			order:      "ORDER BY `id` ASC",
+			size:       DefaultConnectionSize,
+		},
+		{
+			name:       "page size is reduced to the maximum",
+			args:       ConnectionArgs{First: int32Ptr(MaxPageSize + 1)},
+			pageParams: []interface{}{},
+			order:      "ORDER BY `id` ASC",
+			size:       MaxPageSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := BuildConnectionQuery(context.Background(), tt.args, testColumns, "id", database.MySQL{})
+			if err != nil {
+				t.Fatalf("BuildConnectionQuery() failed: %v", err)
+			}
+			if q.PageWhere != tt.pageWhere || !reflect.DeepEqual(q.PageParams, tt.pageParams) {
+				t.Errorf("page where = (%q, %v), want (%q, %v)", q.PageWhere, q.PageParams, tt.pageWhere, tt.pageParams)
+			}
+			if q.Order != tt.order || q.Size != tt.size || q.Backward != tt.backward {
+				t.Errorf("order, size, backward = (%q, %d, %v), want (%q, %d, %v)", q.Order, q.Size, q.Backward, tt.order, tt.size, tt.backward)
+			}
+		})
+	}
+}
+
+func TestBuildConnectionQueryRejectsInvalidArguments(t *testing.T) {
+	negative := int32(-1)
+	tests := []struct {
+		name string
+		args ConnectionArgs
+	}{
+		{"cursor that is not base64", ConnectionArgs{After: strPtr("not a cursor!")}},
+		{"cursor that is not a JSON list", ConnectionArgs{After: strPtr("e30")}},
+		{"cursor of another sort order", ConnectionArgs{Before: strPtr(cursorOf([]SortKey{{Column: "name"}, {Column: "id"}}, nil))}},
+		{"negative first", ConnectionArgs{First: &negative}},
+		{"unknown sort field", ConnectionArgs{OrderBy: &[]*SortInput{{Field: "password"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildConnectionQuery(context.Background(), tt.args, testColumns, "id", database.MySQL{})
+			if apperror.CodeOf(err) != apperror.CodeValidation {
+				t.Errorf("BuildConnectionQuery() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestConnectionPageInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       ConnectionQuery
+		hasMore     bool
+		hasNext     bool
+		hasPrevious bool
+	}{
+		{"forward with more rows", ConnectionQuery{}, true, true, false},
+		{"forward after a cursor", ConnectionQuery{HasAfter: true}, false, false, true},
+		{"backward with more rows", ConnectionQuery{Backward: true}, true, false, true},
+		{"backward before a cursor", ConnectionQuery{Backward: true, HasBefore: true}, false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasNext, hasPrevious := tt.query.PageInfo(tt.hasMore)
+			if hasNext != tt.hasNext || hasPrevious != tt.hasPrevious {
+				t.Errorf("PageInfo(%v) = (%v, %v), want (%v, %v)", tt.hasMore, hasNext, hasPrevious, tt.hasNext, tt.hasPrevious)
+			}
+		})
+	}
+}
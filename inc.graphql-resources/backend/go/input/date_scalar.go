@@ -0,0 +1,60 @@
+package input
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout is the format of Date, e.g. "2024-05-17".
+const dateLayout = "2006-01-02"
+
+// Date is a custom scalar type for the date columns, sent and received as "YYYY-MM-DD", e.g. "2024-05-17".
+type Date struct{ t time.Time }
+
+// ImplementsGraphQLType returns the name of the GraphQL type.
+func (Date) ImplementsGraphQLType(name string) bool { return name == "Date" }
+
+// UnmarshalGraphQL is called when a value is received from a client.
+func (d *Date) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("Date must be a string, got %T", input)
+	}
+	t, err := time.ParseInLocation(dateLayout, strings.TrimSpace(s), time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid Date %q, expected format YYYY-MM-DD", s)
+	}
+	d.t = t
+	return nil
+}
+
+// MarshalJSON is called when sending a value to a client.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Value returns the value stored in the database.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// String returns the date in "YYYY-MM-DD" format.
+func (d Date) String() string {
+	return d.t.Format(dateLayout)
+}
+
+// DateFromDB converts a value read from a date column to a Date scalar, or nil for NULL.
+// Some drivers return dates as times at midnight, so only the date of the value is kept.
+func DateFromDB(value *string) (*Date, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := parseTime(*value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q in the database", *value)
+	}
+	return &Date{t: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)}, nil
+}
@@ -0,0 +1,94 @@
+package input
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"graphqlapplication/constant"
+	"strings"
+	"time"
+)
+
+// DateTime is a custom scalar type for the timestamp and datetime columns.
+// It is sent to clients in RFC 3339 format with the offset of the time zone of the server, e.g. "2024-05-17T08:30:00+07:00".
+// Clients may send it in RFC 3339 format with any offset, which is converted to the time zone of the server,
+// or without an offset, e.g. "2024-05-17 08:30:00", which is taken as a time of the time zone of the server.
+// The time zone of the server is set with the TZ environment variable.
+type DateTime struct{ t time.Time }
+
+// ImplementsGraphQLType returns the name of the GraphQL type.
+func (DateTime) ImplementsGraphQLType(name string) bool { return name == "DateTime" }
+
+// UnmarshalGraphQL is called when a value is received from a client.
+func (d *DateTime) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("DateTime must be a string, got %T", input)
+	}
+	t, err := parseTime(s)
+	if err != nil {
+		return fmt.Errorf("invalid DateTime %q, expected RFC 3339 format such as \"2024-05-17T08:30:00+07:00\"", s)
+	}
+	d.t = t.In(time.Local)
+	return nil
+}
+
+// MarshalJSON is called when sending a value to a client.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.t.Format(time.RFC3339))
+}
+
+// Value returns the value stored in the database: the time in the time zone of the server, in the format of the audit columns.
+func (d DateTime) Value() (driver.Value, error) {
+	return d.t.Format(constant.DateTimeFormat), nil
+}
+
+// Time returns the time held by the DateTime scalar.
+func (d DateTime) Time() time.Time {
+	return d.t
+}
+
+// DateTimeFromDB converts a value read from a timestamp or datetime column to a DateTime scalar, or nil for NULL.
+// Like the audit columns, the value is taken as a time of the time zone of the server; the offset reported by some drivers is ignored.
+func DateTimeFromDB(value *string) (*DateTime, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := parseTime(*value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid datetime %q in the database", *value)
+	}
+	return &DateTime{t: wallClock(t)}, nil
+}
+
+// timeLayouts are the formats accepted for times: RFC 3339, the format of the audit columns,
+// and the formats the database drivers return times in.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	constant.DateTimeFormat,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	dateLayout,
+}
+
+// parseTime parses a time in one of timeLayouts. A time without an offset is taken as a time of the time zone of the server.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var err error
+	for _, layout := range timeLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// wallClock returns the time with the same date and clock as t in the time zone of the server.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
@@ -0,0 +1,82 @@
+package input
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decimalPattern matches the decimal numbers accepted by Decimal, e.g. "-12", "12.50" and ".5".
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// Decimal is a custom scalar type for the decimal and numeric columns.
+// It keeps the digits of the number as text, so no precision is lost as with Float.
+// It is sent to clients as a string, e.g. "12.50", and clients may send it as a string or as a number.
+type Decimal struct{ s string }
+
+// ImplementsGraphQLType returns the name of the GraphQL type.
+func (Decimal) ImplementsGraphQLType(name string) bool { return name == "Decimal" }
+
+// UnmarshalGraphQL is called when a value is received from a client.
+func (d *Decimal) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case string:
+		s := strings.TrimPrefix(strings.TrimSpace(v), "+")
+		if !decimalPattern.MatchString(s) {
+			return fmt.Errorf("invalid Decimal %q", v)
+		}
+		d.s = s
+	case int32:
+		d.s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		d.s = strconv.FormatInt(v, 10)
+	case int:
+		d.s = strconv.Itoa(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid Decimal %v", v)
+		}
+		d.s = strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return d.UnmarshalGraphQL(string(v))
+	default:
+		return fmt.Errorf("Decimal must be a string or a number, got %T", input)
+	}
+	return nil
+}
+
+// MarshalJSON is called when sending a value to a client.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.s)
+}
+
+// Value returns the value stored in the database.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.s, nil
+}
+
+// String returns the digits of the number.
+func (d Decimal) String() string {
+	return d.s
+}
+
+// Float64 returns the number as a float64, e.g. to check it against the minimum and maximum of a validation rule.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(d.s, 64)
+}
+
+// DecimalFromDB converts a value read from a decimal column to a Decimal scalar, or nil for NULL.
+func DecimalFromDB(value *string) (*Decimal, error) {
+	if value == nil {
+		return nil, nil
+	}
+	d := &Decimal{}
+	if err := d.UnmarshalGraphQL(*value); err != nil {
+		return nil, fmt.Errorf("invalid decimal %q in the database", *value)
+	}
+	return d, nil
+}
@@ -1,14 +1,29 @@
 package input
 
 // FilterInput corresponds to the GraphQL FilterInput type.
+// A filter compares Field with Value and may group other filters with And, Or and Not.
 type FilterInput struct {
-	Field    string
-	Value    *Any // Must be a pointer for custom scalar unmarshalling
-	Operator *string
+	Field      *string
+	Value      *Any // Must be a pointer for custom scalar unmarshalling
+	Operator   *string
+	IgnoreCase *bool // Compares text case-insensitively
+	And        *[]*FilterInput
+	Or         *[]*FilterInput
+	Not        *FilterInput
 }
 
 // SortInput corresponds to the GraphQL SortInput type.
 type SortInput struct {
 	Field     string
 	Direction *string
-}
\ No newline at end of file
+}
+
+// ListArgs holds the arguments of list queries and of the fields that list related records.
+type ListArgs struct {
+	Limit   *int32
+	Offset  *int32
+	Page    *int32
+	Size    *int32
+	OrderBy *[]*SortInput
+	Filter  *[]*FilterInput
+}
@@ -0,0 +1,54 @@
+package input
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON is a custom scalar type for the json columns. It holds any JSON value: an object, a list, a string, a number or a boolean.
+// It is sent to clients as the JSON value itself, not as a string holding it.
+type JSON struct{ raw json.RawMessage }
+
+// ImplementsGraphQLType returns the name of the GraphQL type.
+func (JSON) ImplementsGraphQLType(name string) bool { return name == "JSON" }
+
+// UnmarshalGraphQL is called when a value is received from a client.
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("invalid JSON value: %v", err)
+	}
+	j.raw = raw
+	return nil
+}
+
+// MarshalJSON is called when sending a value to a client.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return j.raw, nil
+}
+
+// Value returns the value stored in the database: the JSON text of the value.
+func (j JSON) Value() (driver.Value, error) {
+	return string(j.raw), nil
+}
+
+// Unmarshal decodes the value into v, like json.Unmarshal.
+func (j JSON) Unmarshal(v interface{}) error {
+	return json.Unmarshal(j.raw, v)
+}
+
+// JSONFromDB converts a value read from a json column to a JSON scalar, or nil for NULL.
+func JSONFromDB(value *string) (*JSON, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if !json.Valid([]byte(*value)) {
+		return nil, errors.New("invalid JSON in the database")
+	}
+	return &JSON{raw: json.RawMessage(*value)}, nil
+}
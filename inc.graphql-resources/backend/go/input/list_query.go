@@ -0,0 +1,66 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"graphqlapplication/database"
+	"strings"
+)
+
+// ListQuery holds the clauses, parameters and pagination of a list query.
+type ListQuery struct {
+	Where  string
+	Order  string
+	Params []interface{}
+	Limit  int32
+	Page   int32
+	Offset int32
+}
+
+// BuildListQuery builds the WHERE and ORDER BY clauses and the pagination of a list query from its arguments.
+func BuildListQuery(ctx context.Context, args ListArgs, columns ColumnMap, dialect database.Dialect) (ListQuery, error) {
+	whereSQL, orderSQL, params, err := BuildQuery(ctx, args.Filter, args.OrderBy, columns, dialect)
+	if err != nil {
+		return ListQuery{}, err
+	}
+	limit, page, offset, err := GetPagination(ctx, PaginationArgs{
+		Limit:  args.Limit,
+		Offset: args.Offset,
+		Page:   args.Page,
+		Size:   args.Size,
+	})
+	if err != nil {
+		return ListQuery{}, err
+	}
+	return ListQuery{
+		Where:  whereSQL,
+		Order:  orderSQL,
+		Params: params,
+		Limit:  limit,
+		Page:   page,
+		Offset: offset,
+	}, nil
+}
+
+// Key identifies the query. Lists requested with the same key can be fetched together.
+// The parameters are written in Go syntax, so that different values, such as "a b", "c" and "a", "b c", never share a key.
+func (q ListQuery) Key() string {
+	return fmt.Sprintf("%s|%s|%#v|%d|%d", q.Where, q.Order, q.Params, q.Limit, q.Offset)
+}
+
+// WhereIn adds a "column IN (...)" condition to a WHERE clause built by BuildQuery.
+// It returns the new clause and a new slice of parameters; params is left unchanged.
+func WhereIn(whereSQL string, params []interface{}, column string, values []interface{}) (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	condition := fmt.Sprintf("%s IN (%s)", column, placeholders)
+	if whereSQL == "" {
+		whereSQL = "WHERE " + condition
+	} else {
+		whereSQL = "WHERE (" + strings.TrimPrefix(whereSQL, "WHERE ") + ") AND " + condition
+	}
+
+	newParams := make([]interface{}, 0, len(params)+len(values))
+	newParams = append(newParams, params...)
+	newParams = append(newParams, values...)
+	return whereSQL, newParams
+}
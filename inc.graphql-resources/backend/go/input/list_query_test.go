@@ -0,0 +1,113 @@
+package input
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"reflect"
+	"testing"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+	tests := []struct {
+		name    string
+		args    ListArgs
+		want    ListQuery
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: ListArgs{},
+			want: ListQuery{Limit: DefaultLimit, Page: 1},
+		},
+		{
+			name: "filter, order and page",
+			args: ListArgs{
+				Filter:  &[]*FilterInput{{Field: strPtr("name"), Value: anyPtr("a")}},
+				OrderBy: &[]*SortInput{{Field: "createdAt", Direction: strPtr("DESC")}},
+				Limit:   int32Ptr(5),
+				Page:    int32Ptr(3),
+			},
+			want: ListQuery{Where: "WHERE `name` = ?", Order: "ORDER BY `time_create` DESC", Params: []interface{}{"a"}, Limit: 5, Page: 3, Offset: 10},
+		},
+		{
+			name:    "unknown filter field",
+			args:    ListArgs{Filter: &[]*FilterInput{{Field: strPtr("password"), Value: anyPtr("a")}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid page size",
+			args:    ListArgs{Limit: int32Ptr(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildListQuery(context.Background(), tt.args, testColumns, database.MySQL{})
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("BuildListQuery() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildListQuery() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildListQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListQueryKey(t *testing.T) {
+	query := func(params ...interface{}) ListQuery {
+		return ListQuery{Where: "WHERE `name` IN (?, ?)", Params: params, Limit: 10}
+	}
+	tests := []struct {
+		name string
+		a    ListQuery
+		b    ListQuery
+		same bool
+	}{
+		{"same query", query("a", "b"), query("a", "b"), true},
+		{"values that print the same with spaces", query("a b", "c"), query("a", "b c"), false},
+		{"string and number", query("1", "2"), query(1, 2), false},
+		{"different limit", query("a", "b"), ListQuery{Where: "WHERE `name` IN (?, ?)", Params: []interface{}{"a", "b"}, Limit: 20}, false},
+		{"different offset", query("a", "b"), ListQuery{Where: "WHERE `name` IN (?, ?)", Params: []interface{}{"a", "b"}, Limit: 10, Offset: 10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := tt.a.Key() == tt.b.Key(); same != tt.same {
+				t.Errorf("keys %q and %q are the same: %v, want %v", tt.a.Key(), tt.b.Key(), same, tt.same)
+			}
+		})
+	}
+}
+
+func TestWhereIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		where      string
+		params     []interface{}
+		values     []interface{}
+		want       string
+		wantParams []interface{}
+	}{
+		{"without a filter", "", nil, []interface{}{1, 2}, "WHERE `fk` IN (?, ?)", []interface{}{1, 2}},
+		{"with a filter", "WHERE `a` = ? OR `b` = ?", []interface{}{"x", "y"}, []interface{}{1}, "WHERE (`a` = ? OR `b` = ?) AND `fk` IN (?)", []interface{}{"x", "y", 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]interface{}(nil), tt.params...)
+			where, params := WhereIn(tt.where, tt.params, "`fk`", tt.values)
+			if where != tt.want || !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("WhereIn() = (%q, %v), want (%q, %v)", where, params, tt.want, tt.wantParams)
+			}
+			if !reflect.DeepEqual(tt.params, original) {
+				t.Errorf("WhereIn() changed the parameters to %v", tt.params)
+			}
+		})
+	}
+}
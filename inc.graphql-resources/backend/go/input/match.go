@@ -0,0 +1,199 @@
+package input
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Match tells whether a record matches filters, as BuildQuery would select it, without querying the database.
+// values holds the values of the columns of the record, indexed by column name; pointers are dereferenced.
+// The filters should have been checked with BuildQuery first: unknown fields and operators never match.
+// Text is compared case-sensitively unless ignoreCase is set, except by CONTAINS, and a NULL value only matches IS_NULL.
+func Match(filter *[]*FilterInput, values map[string]interface{}, columns ColumnMap) bool {
+	if filter == nil {
+		return true
+	}
+	return matchAll(*filter, values, columns)
+}
+
+// matchAll tells whether a record matches every filter of a list.
+func matchAll(filters []*FilterInput, values map[string]interface{}, columns ColumnMap) bool {
+	for _, f := range filters {
+		if !matchFilter(f, values, columns) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilter tells whether a record matches the condition and the and, or and not groups of a filter.
+func matchFilter(f *FilterInput, values map[string]interface{}, columns ColumnMap) bool {
+	if f == nil {
+		return true
+	}
+	if f.Field != nil && !matchCondition(f, values, columns) {
+		return false
+	}
+	if f.And != nil && !matchAll(*f.And, values, columns) {
+		return false
+	}
+	if f.Or != nil && len(*f.Or) > 0 {
+		matched := false
+		for _, g := range *f.Or {
+			if matchFilter(g, values, columns) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.Not != nil && matchFilter(f.Not, values, columns) {
+		return false
+	}
+	return true
+}
+
+// matchCondition compares the value of the field of a filter with the value of the filter.
+func matchCondition(f *FilterInput, values map[string]interface{}, columns ColumnMap) bool {
+	column, ok := columns.Column(*f.Field)
+	if !ok {
+		return false
+	}
+	actual := derefValue(values[column])
+
+	operator := "EQUALS"
+	if f.Operator != nil {
+		operator = strings.ToUpper(*f.Operator)
+	}
+	switch operator {
+	case "IS_NULL":
+		return actual == nil
+	case "IS_NOT_NULL":
+		return actual != nil
+	}
+
+	// Like BuildQuery, a filter without a value is ignored
+	if f.Value == nil {
+		return true
+	}
+	if actual == nil {
+		return false
+	}
+	expected := f.Value.Value()
+	ignoreCase := f.IgnoreCase != nil && *f.IgnoreCase
+
+	switch operator {
+	case "EQUALS":
+		return compareValues(actual, expected, ignoreCase) == 0
+	case "NOT_EQUALS":
+		return compareValues(actual, expected, ignoreCase) != 0
+	case "CONTAINS", "NOT_CONTAINS", "STARTS_WITH", "ENDS_WITH":
+		text, part := fmt.Sprint(actual), fmt.Sprint(expected)
+		if ignoreCase || operator == "CONTAINS" {
+			text, part = strings.ToLower(text), strings.ToLower(part)
+		}
+		switch operator {
+		case "STARTS_WITH":
+			return strings.HasPrefix(text, part)
+		case "ENDS_WITH":
+			return strings.HasSuffix(text, part)
+		case "NOT_CONTAINS":
+			return !strings.Contains(text, part)
+		}
+		return strings.Contains(text, part)
+	case "GREATER_THAN":
+		return compareValues(actual, expected, ignoreCase) > 0
+	case "GREATER_THAN_OR_EQUALS":
+		return compareValues(actual, expected, ignoreCase) >= 0
+	case "LESS_THAN":
+		return compareValues(actual, expected, ignoreCase) < 0
+	case "LESS_THAN_OR_EQUALS":
+		return compareValues(actual, expected, ignoreCase) <= 0
+	case "BETWEEN":
+		bounds, isList := expected.([]interface{})
+		if !isList || len(bounds) != 2 {
+			return false
+		}
+		return compareValues(actual, bounds[0], ignoreCase) >= 0 && compareValues(actual, bounds[1], ignoreCase) <= 0
+	case "IN", "NOT_IN":
+		list, isList := expected.([]interface{})
+		if !isList {
+			list = []interface{}{expected}
+		}
+		found := false
+		for _, item := range list {
+			if compareValues(actual, item, ignoreCase) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "IN")
+	}
+	return false
+}
+
+// compareValues compares two values as numbers if both are numeric, and as text otherwise.
+// It returns a negative number, zero or a positive number, like strings.Compare.
+func compareValues(a interface{}, b interface{}, ignoreCase bool) int {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	s, t := fmt.Sprint(a), fmt.Sprint(b)
+	if ignoreCase {
+		s, t = strings.ToLower(s), strings.ToLower(t)
+	}
+	return strings.Compare(s, t)
+}
+
+// toFloat converts numbers, numeric text and booleans to float64.
+// Booleans are converted to 1 and 0, as the databases store them.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// derefValue returns the value a pointer points to, or nil for a nil pointer.
+func derefValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return value
+	}
+	if v.IsNil() {
+		return nil
+	}
+	return v.Elem().Interface()
+}
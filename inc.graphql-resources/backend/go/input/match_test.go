@@ -0,0 +1,98 @@
+package input
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	columns := ColumnMap{"name": "name", "price": "price", "active": "active", "note": "note"}
+	price := 12.5
+	values := map[string]interface{}{
+		"name":   strPtr("Blue Shirt"),
+		"price":  &price,
+		"active": true,
+		"note":   (*string)(nil),
+	}
+	boolPtr := func(b bool) *bool { return &b }
+	condition := func(field string, operator string, value interface{}) *FilterInput {
+		f := &FilterInput{Field: strPtr(field), Operator: strPtr(operator)}
+		if value != nil {
+			f.Value = anyPtr(value)
+		}
+		return f
+	}
+	ignoringCase := func(f *FilterInput) *FilterInput {
+		f.IgnoreCase = boolPtr(true)
+		return f
+	}
+	group := func(filters ...*FilterInput) *[]*FilterInput { return &filters }
+
+	tests := []struct {
+		name   string
+		filter *FilterInput
+		want   bool
+	}{
+		{"equals without an operator", &FilterInput{Field: strPtr("name"), Value: anyPtr("Blue Shirt")}, true},
+		{"equals is case-sensitive", condition("name", "EQUALS", "blue shirt"), false},
+		{"equals ignoring case", ignoringCase(condition("name", "EQUALS", "blue shirt")), true},
+		{"operator in lower case", condition("name", "equals", "Blue Shirt"), true},
+		{"not equals", condition("name", "NOT_EQUALS", "Red Shirt"), true},
+		{"not equals is case-sensitive", condition("name", "NOT_EQUALS", "blue shirt"), true},
+		{"contains always ignores case", condition("name", "CONTAINS", "SHIRT"), true},
+		{"contains", condition("name", "CONTAINS", "Hat"), false},
+		{"not contains is case-sensitive", condition("name", "NOT_CONTAINS", "SHIRT"), true},
+		{"not contains ignoring case", ignoringCase(condition("name", "NOT_CONTAINS", "SHIRT")), false},
+		{"starts with", condition("name", "STARTS_WITH", "Blue"), true},
+		{"starts with is case-sensitive", condition("name", "STARTS_WITH", "blue"), false},
+		{"starts with ignoring case", ignoringCase(condition("name", "STARTS_WITH", "blue")), true},
+		{"ends with", condition("name", "ENDS_WITH", "Shirt"), true},
+		{"ends with is case-sensitive", condition("name", "ENDS_WITH", "shirt"), false},
+		{"greater than", condition("price", "GREATER_THAN", 10), true},
+		{"numeric text compares as a number", condition("price", "GREATER_THAN", "9"), true},
+		{"greater than or equals", condition("price", "GREATER_THAN_OR_EQUALS", 12.5), true},
+		{"less than", condition("price", "LESS_THAN", 12.5), false},
+		{"less than or equals", condition("price", "LESS_THAN_OR_EQUALS", "12.50"), true},
+		{"text compares as text", condition("name", "LESS_THAN", "Red"), true},
+		{"between", condition("price", "BETWEEN", []interface{}{10, 20}), true},
+		{"outside between", condition("price", "BETWEEN", []interface{}{13, 20}), false},
+		{"between without two bounds", condition("price", "BETWEEN", []interface{}{10}), false},
+		{"in", condition("price", "IN", []interface{}{1, "12.5"}), true},
+		{"in a single value", condition("name", "IN", "Blue Shirt"), true},
+		{"in is case-sensitive", condition("name", "IN", []interface{}{"blue shirt"}), false},
+		{"not in", condition("price", "NOT_IN", []interface{}{1, 2}), true},
+		{"boolean equals 1", condition("active", "EQUALS", 1), true},
+		{"boolean equals true", condition("active", "EQUALS", true), true},
+		{"boolean does not equal 0", condition("active", "EQUALS", "0"), false},
+		{"is null", condition("note", "IS_NULL", nil), true},
+		{"is not null", condition("note", "IS_NOT_NULL", nil), false},
+		{"null matches no comparison", condition("note", "NOT_EQUALS", "a"), false},
+		{"condition without a value is ignored", condition("name", "EQUALS", nil), true},
+		{"unknown field", condition("password", "EQUALS", "a"), false},
+		{"unknown operator", condition("name", "LIKE", "Blue%"), false},
+		{"and", &FilterInput{And: group(condition("name", "CONTAINS", "blue"), condition("price", "LESS_THAN", 20))}, true},
+		{"and with a failed condition", &FilterInput{And: group(condition("name", "CONTAINS", "blue"), condition("price", "LESS_THAN", 10))}, false},
+		{"or", &FilterInput{Or: group(condition("name", "EQUALS", "Hat"), condition("price", "LESS_THAN", 20))}, true},
+		{"or without a match", &FilterInput{Or: group(condition("name", "EQUALS", "Hat"), condition("price", "LESS_THAN", 10))}, false},
+		{"empty or", &FilterInput{Or: group()}, true},
+		{"not", &FilterInput{Not: condition("name", "EQUALS", "Hat")}, true},
+		{"not of a match", &FilterInput{Not: condition("name", "EQUALS", "Blue Shirt")}, false},
+		{
+			"condition with groups",
+			&FilterInput{
+				Field: strPtr("active"), Value: anyPtr(true),
+				Or:  group(condition("price", "GREATER_THAN", 100), &FilterInput{Not: condition("note", "IS_NOT_NULL", nil)}),
+				Not: condition("name", "STARTS_WITH", "Red"),
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(group(tt.filter), values, columns); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !Match(nil, values, columns) {
+		t.Error("Match() without filters = false, want true")
+	}
+}
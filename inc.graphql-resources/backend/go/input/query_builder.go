@@ -1,67 +1,44 @@
 package input
 
 import (
+	"context"
 	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"graphqlapplication/util"
+	"math"
 	"strings"
 )
 
+// ColumnMap maps the GraphQL field names of an entity to the names of its database columns.
+// Only the fields it contains can be used to filter and sort the entity.
+type ColumnMap map[string]string
+
+// Column returns the column name of a GraphQL field and whether the field is allowed.
+func (c ColumnMap) Column(field string) (string, bool) {
+	column, ok := c[field]
+	return column, ok
+}
+
 // BuildQuery constructs WHERE and ORDER BY clauses for SQL queries based on GraphQL inputs.
 // It returns the WHERE clause, ORDER BY clause, and a slice of parameters for safe querying.
-func BuildQuery(filter *[]*FilterInput, orderBy *[]*SortInput, isPostgres bool) (string, string, []interface{}) {
-	var whereClauses []string
+// The filters of the list are combined with AND; each filter may contain nested and/or/not groups.
+// Fields are resolved through columns; an unknown field results in an error and no query is built.
+// Column names are quoted and LIKE patterns escaped according to the dialect.
+func BuildQuery(ctx context.Context, filter *[]*FilterInput, orderBy *[]*SortInput, columns ColumnMap, dialect database.Dialect) (string, string, []interface{}, error) {
 	var orderClauses []string
 	var params []interface{}
 
 	// Build WHERE clause from filter
-	// Build WHERE clause from filter
+	whereSQL := ""
 	if filter != nil {
-		for _, f := range *filter {
-			if f == nil || f.Value == nil {
-				continue
-			}
-			// Default operator is EQUALS
-			op := "="
-			val := f.Value.Value()
-
-			if f.Operator != nil {
-				operator := fmt.Sprintf("%v", *f.Operator)
-				switch strings.ToUpper(operator) {
-				case "NOT_EQUALS":
-					op = "!="
-				case "CONTAINS":
-					// Use ILIKE for PostgreSQL for case-insensitive search, LIKE for others (e.g., MySQL).
-					if isPostgres {
-						op = "ILIKE"
-					} else {
-						op = "LIKE"
-					}
-					val = fmt.Sprintf("%%%v%%", val)
-				case "GREATER_THAN":
-					op = ">"
-				case "GREATER_THAN_OR_EQUALS":
-					op = ">="
-				case "LESS_THAN":
-					op = "<"
-				case "LESS_THAN_OR_EQUALS":
-					op = "<="
-				case "IN", "NOT_IN":
-					// For IN/NOT_IN, the value is a comma-separated string. We need to create placeholders.
-					valStr := fmt.Sprintf("%v", val)
-					values := strings.Split(valStr, ",")
-					if len(values) > 0 {
-						placeholders := strings.Repeat("?,", len(values))
-						placeholders = strings.TrimSuffix(placeholders, ",")
-						operator := fmt.Sprintf("%v", *f.Operator)
-						whereClauses = append(whereClauses, fmt.Sprintf("%s %s (%s)", f.Field, strings.ToUpper(operator), placeholders))
-						for _, v := range values {
-							params = append(params, strings.TrimSpace(v))
-						}
-					}
-					continue // Skip the generic param append at the end
-				}
-			}
-			whereClauses = append(whereClauses, fmt.Sprintf("%s %s ?", f.Field, op))
-			params = append(params, val)
+		clause, filterParams, err := buildFilters(ctx, *filter, "AND", columns, dialect)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if clause != "" {
+			whereSQL = "WHERE " + clause
+			params = append(params, filterParams...)
 		}
 	}
 
@@ -71,25 +48,206 @@ func BuildQuery(filter *[]*FilterInput, orderBy *[]*SortInput, isPostgres bool)
 			if s == nil || s.Field == "" {
 				continue
 			}
+			column, ok := columns.Column(s.Field)
+			if !ok {
+				return "", "", nil, apperror.Validation(util.T(ctx, "invalid_sort_field", s.Field))
+			}
 			dir := "ASC" // Default direction
 			if s.Direction != nil && strings.ToUpper(*s.Direction) == "DESC" {
 				dir = "DESC"
 			}
-			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", s.Field, dir))
+			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", dialect.QuoteIdentifier(column), dir))
 		}
 	}
 
-	whereSQL := ""
-	if len(whereClauses) > 0 {
-		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
-	}
-
 	orderSQL := ""
 	if len(orderClauses) > 0 {
 		orderSQL = "ORDER BY " + strings.Join(orderClauses, ", ")
 	}
 
-	return whereSQL, orderSQL, params
+	return whereSQL, orderSQL, params, nil
+}
+
+// buildFilters combines the conditions of several filters with the given logical operator ("AND" or "OR").
+// Filters that produce no condition are ignored.
+func buildFilters(ctx context.Context, filters []*FilterInput, logical string, columns ColumnMap, dialect database.Dialect) (string, []interface{}, error) {
+	var clauses []string
+	var params []interface{}
+	for _, f := range filters {
+		clause, filterParams, err := buildFilter(ctx, f, columns, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			clauses = append(clauses, clause)
+			params = append(params, filterParams...)
+		}
+	}
+	return joinClauses(clauses, logical), params, nil
+}
+
+// buildFilter builds the condition of a single filter.
+// The field condition and the and, or and not groups of the filter are combined with AND.
+func buildFilter(ctx context.Context, f *FilterInput, columns ColumnMap, dialect database.Dialect) (string, []interface{}, error) {
+	if f == nil {
+		return "", nil, nil
+	}
+	var clauses []string
+	var params []interface{}
+
+	if f.Field != nil {
+		clause, conditionParams, err := buildCondition(ctx, f, columns, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			clauses = append(clauses, clause)
+			params = append(params, conditionParams...)
+		}
+	}
+
+	groups := []struct {
+		filters *[]*FilterInput
+		logical string
+	}{
+		{f.And, "AND"},
+		{f.Or, "OR"},
+	}
+	for _, group := range groups {
+		if group.filters == nil {
+			continue
+		}
+		clause, groupParams, err := buildFilters(ctx, *group.filters, group.logical, columns, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			clauses = append(clauses, clause)
+			params = append(params, groupParams...)
+		}
+	}
+
+	if f.Not != nil {
+		clause, notParams, err := buildFilter(ctx, f.Not, columns, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if clause != "" {
+			clauses = append(clauses, "NOT ("+clause+")")
+			params = append(params, notParams...)
+		}
+	}
+
+	return joinClauses(clauses, "AND"), params, nil
+}
+
+// buildCondition builds the comparison of a filter's field with its value.
+// It returns an empty clause if the operator requires a value and none is given.
+func buildCondition(ctx context.Context, f *FilterInput, columns ColumnMap, dialect database.Dialect) (string, []interface{}, error) {
+	column, ok := columns.Column(*f.Field)
+	if !ok {
+		return "", nil, apperror.Validation(util.T(ctx, "invalid_filter_field", *f.Field))
+	}
+	column = dialect.QuoteIdentifier(column)
+
+	// Default operator is EQUALS
+	operator := "EQUALS"
+	if f.Operator != nil {
+		operator = strings.ToUpper(*f.Operator)
+	}
+
+	switch operator {
+	case "IS_NULL":
+		return column + " IS NULL", nil, nil
+	case "IS_NOT_NULL":
+		return column + " IS NOT NULL", nil, nil
+	}
+
+	if f.Value == nil {
+		return "", nil, nil
+	}
+	val := f.Value.Value()
+	ignoreCase := f.IgnoreCase != nil && *f.IgnoreCase
+
+	switch operator {
+	case "EQUALS", "NOT_EQUALS":
+		op := "="
+		if operator == "NOT_EQUALS" {
+			op = "!="
+		}
+		if ignoreCase {
+			// The value is lowered here rather than in SQL, since PostgreSQL cannot infer the type of LOWER(?).
+			// The column is converted to text first, so numbers and dates can be compared too.
+			return fmt.Sprintf("LOWER(%s) %s ?", dialect.Text(column), op), []interface{}{strings.ToLower(fmt.Sprint(val))}, nil
+		}
+		return fmt.Sprintf("%s %s ?", column, op), []interface{}{val}, nil
+	case "CONTAINS", "NOT_CONTAINS", "STARTS_WITH", "ENDS_WITH":
+		pattern := dialect.EscapeLike(fmt.Sprint(val))
+		switch operator {
+		case "STARTS_WITH":
+			pattern = pattern + "%"
+		case "ENDS_WITH":
+			pattern = "%" + pattern
+		default:
+			pattern = "%" + pattern + "%"
+		}
+		op := "LIKE"
+		if operator == "NOT_CONTAINS" {
+			op = "NOT LIKE"
+		}
+		// Patterns are matched against the text of the column, so they apply to numbers and dates too.
+		text := dialect.Text(column)
+		if ignoreCase {
+			return fmt.Sprintf("LOWER(%s) %s ? ESCAPE '%s'", text, op, database.LikeEscape), []interface{}{strings.ToLower(pattern)}, nil
+		}
+		// CONTAINS has always been case-insensitive on PostgreSQL.
+		if operator == "CONTAINS" {
+			op = dialect.CaseInsensitiveLike()
+		}
+		return fmt.Sprintf("%s %s ? ESCAPE '%s'", text, op, database.LikeEscape), []interface{}{pattern}, nil
+	case "GREATER_THAN":
+		return column + " > ?", []interface{}{val}, nil
+	case "GREATER_THAN_OR_EQUALS":
+		return column + " >= ?", []interface{}{val}, nil
+	case "LESS_THAN":
+		return column + " < ?", []interface{}{val}, nil
+	case "LESS_THAN_OR_EQUALS":
+		return column + " <= ?", []interface{}{val}, nil
+	case "BETWEEN":
+		values, isList := val.([]interface{})
+		if !isList || len(values) != 2 {
+			return "", nil, apperror.Validation(util.T(ctx, "invalid_between_value", *f.Field))
+		}
+		return column + " BETWEEN ? AND ?", values, nil
+	case "IN", "NOT_IN":
+		// A single value is treated as a list with one element.
+		values, isList := val.([]interface{})
+		if !isList {
+			values = []interface{}{val}
+		}
+		if len(values) == 0 {
+			// Nothing is in an empty list, and everything is not in it.
+			if operator == "IN" {
+				return "1 = 0", nil, nil
+			}
+			return "1 = 1", nil, nil
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		return fmt.Sprintf("%s %s (%s)", column, strings.Replace(operator, "_", " ", 1), placeholders), values, nil
+	}
+	return "", nil, apperror.Validation(util.T(ctx, "invalid_filter_operator", operator))
+}
+
+// joinClauses joins conditions with a logical operator.
+// More than one condition is wrapped in parentheses so that the result can be nested safely.
+func joinClauses(clauses []string, logical string) string {
+	switch len(clauses) {
+	case 0:
+		return ""
+	case 1:
+		return clauses[0]
+	}
+	return "(" + strings.Join(clauses, " "+logical+" ") + ")"
 }
 
 // PaginationArgs holds common pagination arguments from GraphQL queries.
@@ -100,10 +258,18 @@ type PaginationArgs struct {
 	Size   *int32
 }
 
+// DefaultLimit is the number of items returned by list queries when neither limit nor size is given.
+const DefaultLimit int32 = 10
+
+// MaxPageSize is the largest number of items a list query or a connection may return.
+// Larger limits are reduced to it. Zero means no limit.
+var MaxPageSize int32 = 100
+
 // GetPagination calculates limit, page, and offset from pagination arguments.
-// It sets default values and allows overriding them.
-func GetPagination(args PaginationArgs) (limit, page, offset int32) {
-	limit = 10 // Default limit
+// It sets default values and allows overriding them. The limit never exceeds MaxPageSize.
+// A limit or a page below 1, or a negative offset, is rejected: the databases read a negative limit as no limit.
+func GetPagination(ctx context.Context, args PaginationArgs) (limit, page, offset int32, err error) {
+	limit = DefaultLimit
 	if args.Limit != nil {
 		limit = *args.Limit
 	}
@@ -111,16 +277,33 @@ func GetPagination(args PaginationArgs) (limit, page, offset int32) {
 	if args.Size != nil {
 		limit = *args.Size
 	}
+	if limit < 1 {
+		return 0, 0, 0, apperror.Validation(util.T(ctx, "invalid_page_size"))
+	}
+	if MaxPageSize > 0 && limit > MaxPageSize {
+		limit = MaxPageSize
+	}
 
 	page = 1 // Default page
 	if args.Page != nil {
 		page = *args.Page
 	}
+	if page < 1 {
+		return 0, 0, 0, apperror.Validation(util.T(ctx, "invalid_page_size"))
+	}
 
-	offset = (page - 1) * limit // Calculate offset from page and limit
+	// Calculate offset from page and limit
+	pageOffset := (int64(page) - 1) * int64(limit)
+	if pageOffset > math.MaxInt32 {
+		return 0, 0, 0, apperror.Validation(util.T(ctx, "invalid_page_size"))
+	}
+	offset = int32(pageOffset)
 	if args.Offset != nil {
 		offset = *args.Offset // Allow direct offset override
 	}
+	if offset < 0 {
+		return 0, 0, 0, apperror.Validation(util.T(ctx, "invalid_page_size"))
+	}
 
-	return limit, page, offset
+	return limit, page, offset, nil
 }
\ No newline at end of file
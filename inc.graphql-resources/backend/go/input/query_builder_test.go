@@ -0,0 +1,240 @@
+package input
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"graphqlapplication/database"
+	"math"
+	"reflect"
+	"testing"
+)
+
+var testColumns = ColumnMap{
+	"name":      "name",
+	"createdAt": "time_create",
+}
+
+func strPtr(s string) *string { return &s }
+
+func anyPtr(v interface{}) *Any { return &Any{v: v} }
+
+func TestBuildQueryWhitelist(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  []*FilterInput
+		orderBy []*SortInput
+		where   string
+		order   string
+		wantErr bool
+	}{
+		{
+			name:   "known filter field is mapped to its column",
+			filter: []*FilterInput{{Field: strPtr("createdAt"), Value: anyPtr("2024-01-01")}},
+			where:  "WHERE `time_create` = ?",
+		},
+		{
+			name:    "known sort field is mapped to its column",
+			orderBy: []*SortInput{{Field: "createdAt", Direction: strPtr("desc")}, {Field: "name"}},
+			order:   "ORDER BY `time_create` DESC, `name` ASC",
+		},
+		{
+			name:    "unknown filter field",
+			filter:  []*FilterInput{{Field: strPtr("password"), Value: anyPtr("x")}},
+			wantErr: true,
+		},
+		{
+			name:    "column name instead of field name",
+			filter:  []*FilterInput{{Field: strPtr("time_create"), Value: anyPtr("x")}},
+			wantErr: true,
+		},
+		{
+			name:    "SQL in filter field",
+			filter:  []*FilterInput{{Field: strPtr("name = name OR 1"), Value: anyPtr("x")}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sort field",
+			orderBy: []*SortInput{{Field: "name; DROP TABLE admin"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter *[]*FilterInput
+			if tt.filter != nil {
+				filter = &tt.filter
+			}
+			var orderBy *[]*SortInput
+			if tt.orderBy != nil {
+				orderBy = &tt.orderBy
+			}
+			where, order, _, err := BuildQuery(context.Background(), filter, orderBy, testColumns, database.MySQL{})
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("BuildQuery() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildQuery() failed: %v", err)
+			}
+			if where != tt.where || order != tt.order {
+				t.Errorf("BuildQuery() = (%q, %q), want (%q, %q)", where, order, tt.where, tt.order)
+			}
+		})
+	}
+}
+
+func TestBuildQueryGroups(t *testing.T) {
+	name := func(value string) *FilterInput { return &FilterInput{Field: strPtr("name"), Value: anyPtr(value)} }
+	tests := []struct {
+		name   string
+		filter []*FilterInput
+		where  string
+		params []interface{}
+	}{
+		{
+			name:   "filters of the list are combined with AND",
+			filter: []*FilterInput{name("a"), name("b")},
+			where:  "WHERE (`name` = ? AND `name` = ?)",
+			params: []interface{}{"a", "b"},
+		},
+		{
+			name:   "or group",
+			filter: []*FilterInput{{Or: &[]*FilterInput{name("a"), name("b")}}},
+			where:  "WHERE (`name` = ? OR `name` = ?)",
+			params: []interface{}{"a", "b"},
+		},
+		{
+			name:   "not group",
+			filter: []*FilterInput{{Not: name("a")}},
+			where:  "WHERE NOT (`name` = ?)",
+			params: []interface{}{"a"},
+		},
+		{
+			name: "nested groups and a field condition",
+			filter: []*FilterInput{{
+				Field: strPtr("name"),
+				Value: anyPtr("a"),
+				Or:    &[]*FilterInput{name("b"), {And: &[]*FilterInput{name("c"), {Not: name("d")}}}},
+			}},
+			where:  "WHERE (`name` = ? AND (`name` = ? OR (`name` = ? AND NOT (`name` = ?))))",
+			params: []interface{}{"a", "b", "c", "d"},
+		},
+		{
+			name:   "empty groups produce no condition",
+			filter: []*FilterInput{{And: &[]*FilterInput{}}, {Or: &[]*FilterInput{nil}}},
+			where:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, _, params, err := BuildQuery(context.Background(), &tt.filter, nil, testColumns, database.MySQL{})
+			if err != nil {
+				t.Fatalf("BuildQuery() failed: %v", err)
+			}
+			if where != tt.where || !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("BuildQuery() = (%q, %v), want (%q, %v)", where, params, tt.where, tt.params)
+			}
+		})
+	}
+}
+
+func TestBuildConditionOperators(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	tests := []struct {
+		name       string
+		dialect    database.Dialect
+		operator   string
+		value      interface{}
+		ignoreCase bool
+		where      string
+		params     []interface{}
+		wantErr    bool
+	}{
+		{"equals is the default", database.MySQL{}, "", "a", false, "`name` = ?", []interface{}{"a"}, false},
+		{"is null needs no value", database.MySQL{}, "IS_NULL", nil, false, "`name` IS NULL", nil, false},
+		{"missing value produces no condition", database.MySQL{}, "EQUALS", nil, false, "", nil, false},
+		{"contains escapes the wildcards", database.MySQL{}, "CONTAINS", "50%_off!", false, "`name` LIKE ? ESCAPE '!'", []interface{}{"%50!%!_off!!%"}, false},
+		{"starts with", database.MySQL{}, "STARTS_WITH", "ab", false, "`name` LIKE ? ESCAPE '!'", []interface{}{"ab%"}, false},
+		{"ends with", database.MySQL{}, "ENDS_WITH", "ab", false, "`name` LIKE ? ESCAPE '!'", []interface{}{"%ab"}, false},
+		{"not contains", database.MySQL{}, "NOT_CONTAINS", "ab", false, "`name` NOT LIKE ? ESCAPE '!'", []interface{}{"%ab%"}, false},
+		{"equals ignoring case lowers the value", database.MySQL{}, "EQUALS", "AbC", true, "LOWER(`name`) = ?", []interface{}{"abc"}, false},
+		{"starts with ignoring case", database.MySQL{}, "STARTS_WITH", "AB", true, "LOWER(`name`) LIKE ? ESCAPE '!'", []interface{}{"ab%"}, false},
+		{"contains on PostgreSQL", database.Postgres{}, "CONTAINS", "a_b", false, `CAST("name" AS TEXT) ILIKE ? ESCAPE '!'`, []interface{}{"%a!_b%"}, false},
+		{"equals ignoring case on PostgreSQL casts the column", database.Postgres{}, "NOT_EQUALS", 10, true, `LOWER(CAST("name" AS TEXT)) != ?`, []interface{}{"10"}, false},
+		{"contains on SQL Server escapes ranges", database.SQLServer{}, "CONTAINS", "[a]", false, "[name] LIKE ? ESCAPE '!'", []interface{}{"%![a]%"}, false},
+		{"between", database.MySQL{}, "BETWEEN", []interface{}{1, 5}, false, "`name` BETWEEN ? AND ?", []interface{}{1, 5}, false},
+		{"between needs two values", database.MySQL{}, "BETWEEN", []interface{}{1}, false, "", nil, true},
+		{"in", database.MySQL{}, "IN", []interface{}{"a", "b,c"}, false, "`name` IN (?, ?)", []interface{}{"a", "b,c"}, false},
+		{"in with a single value", database.MySQL{}, "IN", "a", false, "`name` IN (?)", []interface{}{"a"}, false},
+		{"in an empty list", database.MySQL{}, "IN", []interface{}{}, false, "1 = 0", nil, false},
+		{"not in an empty list", database.MySQL{}, "NOT_IN", []interface{}{}, false, "1 = 1", nil, false},
+		{"unknown operator", database.MySQL{}, "SOUNDS_LIKE", "a", false, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FilterInput{Field: strPtr("name"), IgnoreCase: boolPtr(tt.ignoreCase)}
+			if tt.operator != "" {
+				f.Operator = strPtr(tt.operator)
+			}
+			if tt.value != nil {
+				f.Value = anyPtr(tt.value)
+			}
+			where, params, err := buildCondition(context.Background(), f, testColumns, tt.dialect)
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("buildCondition() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildCondition() failed: %v", err)
+			}
+			if where != tt.where || !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("buildCondition() = (%q, %v), want (%q, %v)", where, params, tt.where, tt.params)
+			}
+		})
+	}
+}
+
+func TestGetPagination(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+	tests := []struct {
+		name    string
+		args    PaginationArgs
+		limit   int32
+		page    int32
+		offset  int32
+		wantErr bool
+	}{
+		{"defaults", PaginationArgs{}, DefaultLimit, 1, 0, false},
+		{"page", PaginationArgs{Limit: int32Ptr(20), Page: int32Ptr(3)}, 20, 3, 40, false},
+		{"size is an alias of limit", PaginationArgs{Size: int32Ptr(5), Page: int32Ptr(2)}, 5, 2, 5, false},
+		{"offset overrides the page", PaginationArgs{Page: int32Ptr(3), Offset: int32Ptr(7)}, DefaultLimit, 3, 7, false},
+		{"limit is reduced to the maximum", PaginationArgs{Limit: int32Ptr(MaxPageSize + 1)}, MaxPageSize, 1, 0, false},
+		{"zero limit", PaginationArgs{Limit: int32Ptr(0)}, 0, 0, 0, true},
+		{"negative limit", PaginationArgs{Limit: int32Ptr(-1)}, 0, 0, 0, true},
+		{"negative size", PaginationArgs{Size: int32Ptr(-1)}, 0, 0, 0, true},
+		{"zero page", PaginationArgs{Page: int32Ptr(0)}, 0, 0, 0, true},
+		{"negative offset", PaginationArgs{Offset: int32Ptr(-1)}, 0, 0, 0, true},
+		{"page offset beyond an int32", PaginationArgs{Limit: int32Ptr(100), Page: int32Ptr(math.MaxInt32)}, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, page, offset, err := GetPagination(context.Background(), tt.args)
+			if tt.wantErr {
+				if apperror.CodeOf(err) != apperror.CodeValidation {
+					t.Fatalf("GetPagination() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPagination() failed: %v", err)
+			}
+			if limit != tt.limit || page != tt.page || offset != tt.offset {
+				t.Errorf("GetPagination() = (%d, %d, %d), want (%d, %d, %d)", limit, page, offset, tt.limit, tt.page, tt.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package input
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+// scalar is a custom scalar as used by the resolvers: received from clients, sent to clients and stored in the database.
+type scalar interface {
+	UnmarshalGraphQL(input interface{}) error
+	json.Marshaler
+	driver.Valuer
+}
+
+// useZone sets the time zone of the server for the duration of the test.
+func useZone(t *testing.T, offset int) {
+	t.Helper()
+	previous := time.Local
+	time.Local = time.FixedZone("server", offset)
+	t.Cleanup(func() { time.Local = previous })
+}
+
+// checkScalar checks what a scalar sends to clients and stores in the database.
+func checkScalar(t *testing.T, s scalar, wantJSON string, wantValue driver.Value) {
+	t.Helper()
+	data, err := s.MarshalJSON()
+	if err != nil || string(data) != wantJSON {
+		t.Errorf("MarshalJSON() = (%s, %v), want %s", data, err, wantJSON)
+	}
+	value, err := s.Value()
+	if err != nil || value != wantValue {
+		t.Errorf("Value() = (%v, %v), want %v", value, err, wantValue)
+	}
+}
+
+func TestScalarUnmarshalGraphQL(t *testing.T) {
+	useZone(t, 7*60*60)
+	tests := []struct {
+		name    string
+		scalar  scalar
+		input   interface{}
+		json    string
+		value   driver.Value
+		wantErr bool
+	}{
+		{"date", &Date{}, "2024-05-17", `"2024-05-17"`, "2024-05-17", false},
+		{"date with spaces", &Date{}, " 2024-05-17 ", `"2024-05-17"`, "2024-05-17", false},
+		{"date in another format", &Date{}, "17/05/2024", "", nil, true},
+		{"date that does not exist", &Date{}, "2024-02-30", "", nil, true},
+		{"date that is not a string", &Date{}, int32(20240517), "", nil, true},
+		{"datetime with an offset is converted", &DateTime{}, "2024-05-17T01:30:00Z", `"2024-05-17T08:30:00+07:00"`, "2024-05-17 08:30:00", false},
+		{"datetime without an offset", &DateTime{}, "2024-05-17 08:30:00", `"2024-05-17T08:30:00+07:00"`, "2024-05-17 08:30:00", false},
+		{"datetime that is not a time", &DateTime{}, "yesterday", "", nil, true},
+		{"decimal", &Decimal{}, "12.50", `"12.50"`, "12.50", false},
+		{"decimal with a sign", &Decimal{}, "+.5", `".5"`, ".5", false},
+		{"decimal from an Int", &Decimal{}, int32(-3), `"-3"`, "-3", false},
+		{"decimal from a Float", &Decimal{}, 1.25, `"1.25"`, "1.25", false},
+		{"decimal with an exponent", &Decimal{}, "1e5", "", nil, true},
+		{"decimal that is not a number", &Decimal{}, "12,50", "", nil, true},
+		{"decimal that is not finite", &Decimal{}, math.Inf(1), "", nil, true},
+		{"decimal that is a boolean", &Decimal{}, true, "", nil, true},
+		{"JSON object", &JSON{}, map[string]interface{}{"a": []interface{}{int32(1), "b"}}, `{"a":[1,"b"]}`, `{"a":[1,"b"]}`, false},
+		{"JSON string", &JSON{}, "text", `"text"`, `"text"`, false},
+		{"JSON that cannot be encoded", &JSON{}, math.NaN(), "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scalar.UnmarshalGraphQL(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("UnmarshalGraphQL() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalGraphQL() failed: %v", err)
+			}
+			checkScalar(t, tt.scalar, tt.json, tt.value)
+		})
+	}
+}
+
+func TestScalarFromDB(t *testing.T) {
+	useZone(t, 7*60*60)
+	str := func(s string) *string { return &s }
+	// fromDB converts the result of a FromDB function to a scalar, keeping a nil pointer as a nil interface.
+	fromDB := func(s scalar, isNil bool, err error) (scalar, error) {
+		if isNil {
+			return nil, err
+		}
+		return s, err
+	}
+	date := func(v *string) (scalar, error) { d, err := DateFromDB(v); return fromDB(d, d == nil, err) }
+	dateTime := func(v *string) (scalar, error) { d, err := DateTimeFromDB(v); return fromDB(d, d == nil, err) }
+	decimal := func(v *string) (scalar, error) { d, err := DecimalFromDB(v); return fromDB(d, d == nil, err) }
+	jsonValue := func(v *string) (scalar, error) { j, err := JSONFromDB(v); return fromDB(j, j == nil, err) }
+
+	tests := []struct {
+		name    string
+		convert func(*string) (scalar, error)
+		value   *string
+		isNil   bool
+		json    string
+		wantErr bool
+	}{
+		{"NULL date", date, nil, true, "", false},
+		{"date", date, str("2024-05-17"), false, `"2024-05-17"`, false},
+		{"date returned as a time at midnight", date, str("2024-05-17T00:00:00Z"), false, `"2024-05-17"`, false},
+		{"invalid date", date, str("0000"), false, "", true},
+		{"NULL datetime", dateTime, nil, true, "", false},
+		{"datetime keeps its clock time", dateTime, str("2024-05-17T08:30:00Z"), false, `"2024-05-17T08:30:00+07:00"`, false},
+		{"datetime in the format of the audit columns", dateTime, str("2024-05-17 08:30:00"), false, `"2024-05-17T08:30:00+07:00"`, false},
+		{"datetime with fractional seconds", dateTime, str("2024-05-17 08:30:00.5"), false, `"2024-05-17T08:30:00+07:00"`, false},
+		{"NULL decimal", decimal, nil, true, "", false},
+		{"decimal keeps its digits", decimal, str("12345678901234567890.10"), false, `"12345678901234567890.10"`, false},
+		{"invalid decimal", decimal, str("abc"), false, "", true},
+		{"NULL JSON", jsonValue, nil, true, "", false},
+		{"JSON", jsonValue, str(`{"a": 1}`), false, `{"a": 1}`, false},
+		{"invalid JSON", jsonValue, str(`{"a":`), false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.convert(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("conversion succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("conversion failed: %v", err)
+			}
+			if (s == nil) != tt.isNil {
+				t.Fatalf("conversion = %v, want nil: %v", s, tt.isNil)
+			}
+			if s == nil {
+				return
+			}
+			if data, err := s.MarshalJSON(); err != nil || string(data) != tt.json {
+				t.Errorf("MarshalJSON() = (%s, %v), want %s", data, err, tt.json)
+			}
+		})
+	}
+}
+
+func TestDecimalFloat64(t *testing.T) {
+	d := Decimal{}
+	if err := d.UnmarshalGraphQL("12.50"); err != nil {
+		t.Fatalf("UnmarshalGraphQL() failed: %v", err)
+	}
+	if f, err := d.Float64(); err != nil || f != 12.5 {
+		t.Errorf("Float64() = (%v, %v), want 12.5", f, err)
+	}
+}
+
+func TestAnyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		json  string
+	}{
+		{"string", "a", `"a"`},
+		{"number", int32(1), `1`},
+		{"boolean", true, `true`},
+		{"null", nil, `null`},
+		{"list", []interface{}{"a", 1.5}, `["a",1.5]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewAny(tt.value).MarshalJSON()
+			if err != nil || string(data) != tt.json {
+				t.Errorf("MarshalJSON() = (%s, %v), want %s", data, err, tt.json)
+			}
+		})
+	}
+}
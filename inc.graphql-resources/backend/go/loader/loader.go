@@ -0,0 +1,115 @@
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultWait is how long a loader collects keys before it fetches them in one batch.
+// Sibling fields are resolved concurrently, so a short wait is enough to collect the keys of a whole list.
+const DefaultWait = 2 * time.Millisecond
+
+// DefaultMaxBatch is the maximum number of keys fetched in one batch.
+// It keeps the number of placeholders of a "WHERE pk IN (...)" query within the limits of the databases.
+const DefaultMaxBatch = 500
+
+// BatchFunc fetches the values of several keys at once.
+// Keys missing from the returned map resolve to the zero value without an error.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader batches the keys requested within a short period and caches the results.
+// A loader lives for a single request, so cached values never outlive the request that loaded them.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+// result is the outcome of loading a single key. done is closed once value and err are set.
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// batch holds the keys waiting to be fetched together.
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	once    sync.Once
+}
+
+// New creates a loader that fetches keys with fetch.
+func New[K comparable, V any](fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     DefaultWait,
+		maxBatch: DefaultMaxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of a key. Keys loaded before are served from the cache,
+// other keys are fetched together with the keys requested by concurrent callers.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+
+		b := l.pending
+		if b == nil {
+			b = &batch[K, V]{}
+			l.pending = b
+			go l.dispatchAfterWait(ctx, b)
+		}
+		b.keys = append(b.keys, key)
+		b.results = append(b.results, res)
+		if len(b.keys) >= l.maxBatch {
+			l.pending = nil
+			go l.dispatch(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatchAfterWait fetches a batch once the wait period is over, unless it was already fetched because it was full.
+func (l *Loader[K, V]) dispatchAfterWait(ctx context.Context, b *batch[K, V]) {
+	time.Sleep(l.wait)
+	l.mu.Lock()
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+	l.dispatch(ctx, b)
+}
+
+// dispatch fetches the keys of a batch and delivers the results to the waiting callers.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		values, err := l.fetch(ctx, b.keys)
+		for i, key := range b.keys {
+			res := b.results[i]
+			if err != nil {
+				res.err = err
+			} else {
+				res.value = values[key]
+			}
+			close(res.done)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a batch function that returns the double of each key except 0 and records the batches it fetched.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *recorder) fetch(ctx context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	r.batches = append(r.batches, batch)
+	if r.err != nil {
+		return nil, r.err
+	}
+	values := make(map[int]int)
+	for _, key := range keys {
+		if key != 0 {
+			values[key] = key * 2
+		}
+	}
+	return values, nil
+}
+
+// loadAll loads the keys concurrently, as sibling fields are resolved, and returns the values and errors by position.
+func loadAll(l *Loader[int, int], keys []int) ([]int, []error) {
+	values := make([]int, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i, key int) {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(context.Background(), key)
+		}(i, key)
+	}
+	wg.Wait()
+	return values, errs
+}
+
+func TestLoaderBatching(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBatch int
+		keys     []int
+		values   []int
+		batches  int
+	}{
+		{"concurrent keys are fetched in one batch", DefaultMaxBatch, []int{1, 2, 3}, []int{2, 4, 6}, 1},
+		{"duplicate keys are fetched once", DefaultMaxBatch, []int{1, 1, 2, 2}, []int{2, 2, 4, 4}, 1},
+		{"missing keys resolve to the zero value", DefaultMaxBatch, []int{0, 1}, []int{0, 2}, 1},
+		{"full batches are fetched at once", 2, []int{1, 2, 3, 4, 5}, []int{2, 4, 6, 8, 10}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			l := New(r.fetch)
+			l.wait = 50 * time.Millisecond
+			l.maxBatch = tt.maxBatch
+
+			values, errs := loadAll(l, tt.keys)
+			for i := range tt.keys {
+				if errs[i] != nil || values[i] != tt.values[i] {
+					t.Errorf("Load(%d) = (%d, %v), want (%d, nil)", tt.keys[i], values[i], errs[i], tt.values[i])
+				}
+			}
+			if len(r.batches) != tt.batches {
+				t.Errorf("fetched %d batches %v, want %d", len(r.batches), r.batches, tt.batches)
+			}
+			for _, batch := range r.batches {
+				if len(batch) > tt.maxBatch {
+					t.Errorf("batch %v has more than %d keys", batch, tt.maxBatch)
+				}
+			}
+		})
+	}
+}
+
+func TestLoaderCache(t *testing.T) {
+	r := &recorder{}
+	l := New(r.fetch)
+	loadAll(l, []int{1, 2})
+	values, _ := loadAll(l, []int{2, 3})
+	if values[0] != 4 || values[1] != 6 {
+		t.Errorf("values = %v, want [4 6]", values)
+	}
+	if len(r.batches) != 2 || len(r.batches[1]) != 1 || r.batches[1][0] != 3 {
+		t.Errorf("batches = %v, want the cached key 2 not to be fetched again", r.batches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	r := &recorder{err: errors.New("connection lost")}
+	l := New(r.fetch)
+	_, errs := loadAll(l, []int{1, 2})
+	for i, err := range errs {
+		if !errors.Is(err, r.err) {
+			t.Errorf("error of key %d = %v, want %v", i+1, err, r.err)
+		}
+	}
+}
+
+func TestLoaderCanceledContext(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	l := New(func(ctx context.Context, keys []int) (map[int]int, error) {
+		<-block
+		return nil, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,37 @@
+package loader
+
+import (
+	"context"
+	"graphqlapplication/constant"
+	"sync"
+)
+
+// Registry holds the loaders of a single request, one per name.
+type Registry struct {
+	mu      sync.Mutex
+	loaders map[string]interface{}
+}
+
+// WithRegistry returns a copy of ctx that carries a new, empty registry.
+// The GraphQL handler calls it for every request, so loaders never share results between requests.
+func WithRegistry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, constant.Loaders, &Registry{loaders: make(map[string]interface{})}) // NOSONAR
+}
+
+// Get returns the loader registered under name in the registry of ctx, creating it with fetch if needed.
+// Without a registry in ctx, a new loader is returned, so lookups still work but are neither batched nor cached.
+func Get[K comparable, V any](ctx context.Context, name string, fetch BatchFunc[K, V]) *Loader[K, V] {
+	registry, ok := ctx.Value(constant.Loaders).(*Registry)
+	if !ok {
+		return New(fetch)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if l, ok := registry.loaders[name].(*Loader[K, V]); ok {
+		return l
+	}
+	l := New(fetch)
+	registry.loaders[name] = l
+	return l
+}
@@ -5,24 +5,33 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
-	"net"
-	"net/http"
-	"os"
-	"path/filepath"
+	"graphqlapplication/apperror"
+	"graphqlapplication/auth"
+	"graphqlapplication/complexity"
+	"graphqlapplication/config"
 	"graphqlapplication/constant"
 	"graphqlapplication/controller"
+	"graphqlapplication/database"
+	"graphqlapplication/explorer"
 	"graphqlapplication/handler"
+	"graphqlapplication/input"
+	"graphqlapplication/loader"
+	"graphqlapplication/persisted"
 	"graphqlapplication/resolver"
 	"graphqlapplication/util"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	"github.com/gorilla/sessions"
 	"github.com/graph-gophers/graphql-go"
-	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/joho/godotenv"
 	_ "modernc.org/sqlite"
 )
@@ -75,6 +84,77 @@ func authMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// graphqlAuthMiddleware resolves the logged-in admin and exposes it to the resolvers through the context.
+// If the REQUIRE_LOGIN environment variable is set to "true", unauthenticated requests
+// are rejected with a GraphQL error payload before the query is executed.
+func graphqlAuthMiddleware(db *sql.DB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		requireLogin := os.Getenv("REQUIRE_LOGIN") == "true"
+
+		// Translate resolver messages into the language requested by the client
+		if lang := r.Header.Get("X-Language-Id"); lang != "" {
+			ctx = context.WithValue(ctx, constant.LanguageKey, lang) // NOSONAR
+		}
+
+		adminId, _ := ctx.Value(constant.SessionAdminId).(string)
+		if adminId != "" {
+			admin, err := auth.LoadAdmin(ctx, db, adminId)
+			if err == nil {
+				ctx = auth.WithAdmin(ctx, admin)
+			} else if err != sql.ErrNoRows {
+				log.Printf("Failed to load admin %s: %v", adminId, err)
+			}
+		}
+
+		if requireLogin && auth.AdminFromContext(ctx) == nil {
+			lang := r.Header.Get("X-Language-Id")
+			writeGraphQLError(w, http.StatusUnauthorized, util.Translate(lang, "login_required"), "UNAUTHENTICATED")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// correlationMiddleware gives each GraphQL request a correlation id, which is logged with its internal errors
+// and returned to the client in the X-Correlation-Id header and in the extensions of those errors.
+// A correlation id sent by the client, e.g. by a proxy, is kept.
+func correlationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(apperror.CorrelationHeader)
+		if id == "" || len(id) > 64 {
+			id = uuid.New().String()
+		}
+		w.Header().Set(apperror.CorrelationHeader, id)
+		next.ServeHTTP(w, r.WithContext(apperror.WithCorrelationID(r.Context(), id)))
+	})
+}
+
+// loaderMiddleware attaches a new loader registry to each GraphQL request,
+// so relation lookups made while resolving one request are batched and cached together.
+func loaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(loader.WithRegistry(r.Context())))
+	})
+}
+
+// writeGraphQLError writes a GraphQL response containing a single error and no data.
+func writeGraphQLError(w http.ResponseWriter, status int, message string, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": nil,
+		"errors": []map[string]interface{}{
+			{
+				"message":    message,
+				"extensions": map[string]interface{}{"code": code},
+			},
+		},
+	})
+}
+
 func GetClientIP(r *http.Request) string {
 	// Check for X-Forwarded-For header, which can be a comma-separated list.
 	// The client's IP is typically the first one.
@@ -116,12 +196,69 @@ func getDBConfig() (string, string) {
 			os.Getenv("DB_PORT"),
 			os.Getenv("DB_NAME"),
 		)
+	case "postgres", "postgresql", "pgsql":
+		// Use the driver that rebinds '?' placeholders to PostgreSQL's $1, $2, ...
+		driver = database.PostgresDriverName
+		dsn = database.PostgresDSN()
+	case "sqlserver", "mssql", "sqlsrv":
+		// Use the driver that rebinds '?' placeholders to SQL Server's @p1, @p2, ...
+		driver = database.SQLServerDriverName
+		dsn = database.SQLServerDSN()
 	default:
-		log.Fatalf("Unsupported database driver: %s. Supported drivers are 'mysql' and 'sqlite'.", driver)
+		log.Fatalf("Unsupported database driver: %s. Supported drivers are 'mysql', 'sqlite', 'postgres' and 'sqlserver'.", driver)
 	}
 	return driver, dsn
 }
 
+// envInt reads a non-negative number from an environment variable, or returns defaultValue if it is not set or not valid.
+func envInt(name string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// newPersistedQueries creates the registry of persisted queries configured by the environment.
+// GRAPHQL_PERSISTED_QUERY_STORE selects where the queries registered by the clients are kept: "memory" (the default),
+// "file", "sql" or "none". In strict mode, only the queries of the manifest may run.
+func newPersistedQueries(db *sql.DB) *persisted.Registry {
+	var allowlist map[string]string
+	if manifest := os.Getenv("GRAPHQL_PERSISTED_QUERY_MANIFEST"); manifest != "" {
+		var err error
+		allowlist, err = persisted.LoadManifest(manifest)
+		if err != nil {
+			log.Fatalf("Failed to load persisted query manifest: %v", err)
+		}
+	}
+	strict := os.Getenv("GRAPHQL_PERSISTED_QUERY_STRICT") == "true"
+	if strict && allowlist == nil {
+		log.Fatal("GRAPHQL_PERSISTED_QUERY_STRICT requires GRAPHQL_PERSISTED_QUERY_MANIFEST.")
+	}
+
+	var store persisted.Store
+	switch storeType := os.Getenv("GRAPHQL_PERSISTED_QUERY_STORE"); storeType {
+	case "", "memory":
+		store = persisted.NewMemoryStore(envInt("GRAPHQL_PERSISTED_QUERY_CACHE_SIZE", 1000))
+	case "file":
+		dir := os.Getenv("GRAPHQL_PERSISTED_QUERY_DIR")
+		if dir == "" {
+			dir = "persisted-queries"
+		}
+		fileStore, err := persisted.NewFileStore(dir)
+		if err != nil {
+			log.Fatalf("Failed to open persisted query directory %s: %v", dir, err)
+		}
+		store = fileStore
+	case "sql":
+		store = persisted.NewSQLStore(db)
+	case "none":
+	default:
+		log.Fatalf("Unsupported persisted query store: %s. Supported stores are 'memory', 'file', 'sql' and 'none'.", storeType)
+	}
+	return persisted.NewRegistry(store, allowlist, strict)
+}
+
 func registerRoutes(db *sql.DB, store *sessions.CookieStore) {
 	// Read GraphQL schema from file
 	schemaPath := os.Getenv("GRAPHQL_SCHEMA")
@@ -130,12 +267,60 @@ func registerRoutes(db *sql.DB, store *sessions.CookieStore) {
 		log.Fatalf("Failed to read schema file %s: %v", schemaPath, err)
 	}
 
+	// Introspection can be disabled with GRAPHQL_INTROSPECTION=false; superusers may still introspect the schema
+	introspection := os.Getenv("GRAPHQL_INTROSPECTION") != "false"
+	schemaOptions := []graphql.SchemaOpt{graphql.RestrictIntrospection(func(ctx context.Context) bool {
+		return introspection || auth.IsSuperuser(auth.AdminFromContext(ctx))
+	})}
+
+	// graphql-go checks the depth too, in case a request gets past the analyzer
+	maxDepth := envInt("GRAPHQL_MAX_DEPTH", 10)
+	schemaOptions = append(schemaOptions, graphql.MaxDepth(maxDepth))
+
 	// Parse GraphQL schema
-	schema := graphql.MustParseSchema(string(schemaData), resolver.NewRootResolver(db))
+	rootResolver := resolver.NewRootResolver(db)
+	schema := graphql.MustParseSchema(string(schemaData), rootResolver, schemaOptions...)
+
+	// Requests run in a transaction resolve their fields one at a time, so they can share its connection
+	txSchema := graphql.MustParseSchema(string(schemaData), rootResolver, append(schemaOptions, graphql.MaxParallelism(1))...)
+
+	// Limit the depth, the cost and the page size of the requests. Zero disables a limit.
+	input.MaxPageSize = int32(envInt("GRAPHQL_MAX_PAGE_SIZE", 100))
+	analyzer := complexity.NewAnalyzer(schema, complexity.Limits{
+		MaxDepth: maxDepth,
+		MaxCost:  envInt("GRAPHQL_MAX_COST", 10000),
+	})
+
+	// Resolve the queries sent by hash
+	persistedQueries := newPersistedQueries(db)
 
 	// Set handler for GraphQL endpoint
 	graphqlEndpoint := os.Getenv("GRAPHQL_ENDPOINT")
-	http.Handle(graphqlEndpoint, ipMiddleware(&relay.Handler{Schema: schema}))
+	graphqlHandler := &handler.GraphQLHandler{
+		DB:        db,
+		Schema:    schema,
+		TxSchema:  txSchema,
+		Analyzer:  analyzer,
+		Persisted: persistedQueries,
+	}
+	// WebSocket connections to the same endpoint carry subscriptions.
+	// They get no loader registry, which would keep stale records for the lifetime of the connection.
+	subscriptionHandler := &handler.SubscriptionHandler{Schema: schema, Analyzer: analyzer, Persisted: persistedQueries}
+	graphqlRoutes := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler.IsWebSocket(r) {
+			subscriptionHandler.ServeHTTP(w, r)
+			return
+		}
+		loaderMiddleware(graphqlHandler).ServeHTTP(w, r)
+	})
+	http.Handle(graphqlEndpoint, correlationMiddleware(ipMiddleware(graphqlAuthMiddleware(db, graphqlRoutes))))
+
+	// Serve the GraphQL explorer to logged-in admins if GRAPHIQL_ENABLED is "true"
+	if explorer.Enabled() {
+		explorerHandler := ipMiddleware(graphqlAuthMiddleware(db, &explorer.Handler{Endpoint: graphqlEndpoint}))
+		http.Handle(explorer.Path, explorerHandler)
+		http.Handle(explorer.Path+"/", explorerHandler)
+	}
 
 	// Initialize and register authentication handlers
 	authHandler := &handler.AuthHandler{
@@ -154,6 +339,10 @@ func registerRoutes(db *sql.DB, store *sessions.CookieStore) {
 	adminHandler := controller.NewAdminHandler(db, store)
 	http.Handle("/admin", adminHandler)
 
+	// Initialize and register PermissionHandler
+	permissionHandler := controller.NewPermissionHandler(db, store, resolver.EntityNames)
+	http.Handle("/admin-permission", permissionHandler)
+
 	// Initialize and register MessageHandler
 	messageHandler := controller.NewMessageHandler(db, store)
 	http.Handle("/message", messageHandler)
@@ -195,6 +384,9 @@ func registerRoutes(db *sql.DB, store *sessions.CookieStore) {
 	serverPort := os.Getenv("SERVER_PORT")
 	log.Printf("Server is running at: http://localhost:%s", serverPort)
 	log.Printf("GraphQL endpoint is available at http://localhost:%s%s", serverPort, graphqlEndpoint)
+	if explorer.Enabled() {
+		log.Printf("GraphQL explorer is available at http://localhost:%s%s", serverPort, explorer.Path)
+	}
 }
 
 func main() {
@@ -214,8 +406,12 @@ func main() {
 	// Initialize i18n translations
 	util.InitI18n("static/langs/i18n")
 
+	// Select the algorithm used for new password hashes
+	util.InitPasswordHasher(os.Getenv("PASSWORD_HASH_ALGORITHM"))
+
 	// Get database configuration
 	driver, dsn := getDBConfig()
+	config.InitDriver(os.Getenv("DB_DRIVER"))
 
 	// Open database connection
 	db, err := sql.Open(driver, dsn)
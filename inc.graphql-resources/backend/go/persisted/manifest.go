@@ -0,0 +1,63 @@
+package persisted
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// manifestOperation is an operation of a manifest in the format of Apollo.
+type manifestOperation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// LoadManifest reads the allowlist of a registry from a JSON manifest and returns its queries indexed by hash.
+// The manifest is either in the format of Apollo, {"format": "apollo-persisted-query-manifest", "operations": [{"id": ..., "body": ...}]},
+// or a plain object that maps the hashes to the queries. Every hash must be the SHA-256 hash of its query.
+func LoadManifest(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	queries := make(map[string]string)
+	add := func(id string, query string) error {
+		hash := Hash(query)
+		if strings.ToLower(id) != hash {
+			return fmt.Errorf("invalid manifest %s: %s is not the SHA-256 hash of its query", path, id)
+		}
+		queries[hash] = query
+		return nil
+	}
+
+	if rawOperations, ok := entries["operations"]; ok {
+		var operations []manifestOperation
+		if err := json.Unmarshal(rawOperations, &operations); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+		}
+		for _, op := range operations {
+			if err := add(op.ID, op.Body); err != nil {
+				return nil, err
+			}
+		}
+		return queries, nil
+	}
+
+	for id, rawQuery := range entries {
+		var query string
+		if err := json.Unmarshal(rawQuery, &query); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: the query of %s is not a string", path, id)
+		}
+		if err := add(id, query); err != nil {
+			return nil, err
+		}
+	}
+	return queries, nil
+}
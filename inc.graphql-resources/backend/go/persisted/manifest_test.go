@@ -0,0 +1,43 @@
+package persisted
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadManifest(t *testing.T) {
+	hash := Hash(allowedQuery)
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{"Apollo format", `{"format": "apollo-persisted-query-manifest", "version": 1, "operations": [{"id": "` + hash + `", "name": "Items", "type": "query", "body": "` + allowedQuery + `"}]}`, false},
+		{"plain object", `{"` + hash + `": "` + allowedQuery + `"}`, false},
+		{"hash of another query", `{"` + Hash(otherQuery) + `": "` + allowedQuery + `"}`, true},
+		{"query that is not a string", `{"` + hash + `": 1}`, true},
+		{"not JSON", `operations`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest.json")
+			if err := os.WriteFile(path, []byte(tt.manifest), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			queries, err := LoadManifest(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadManifest() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadManifest() failed: %v", err)
+			}
+			if len(queries) != 1 || queries[hash] != allowedQuery {
+				t.Errorf("LoadManifest() = %v, want the query by its hash", queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"graphqlapplication/apperror"
+	"graphqlapplication/util"
+	"log"
+	"strings"
+)
+
+// Codes and messages of the errors of the automatic persisted queries protocol.
+// Clients such as Apollo recognize these errors by their code or by their message, so the messages are not translated.
+const (
+	CodeNotFound        = "PERSISTED_QUERY_NOT_FOUND"
+	CodeNotSupported    = "PERSISTED_QUERY_NOT_SUPPORTED"
+	MessageNotFound     = "PersistedQueryNotFound"
+	MessageNotSupported = "PersistedQueryNotSupported"
+)
+
+// Version is the version of the persistedQuery extension supported by the registry.
+const Version = 1
+
+// Extension is the persistedQuery entry of the extensions of a request.
+type Extension struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// Hash returns the SHA-256 hash of a query, in lowercase hexadecimal, as sent by the clients.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash tells whether a hash is a SHA-256 hash in lowercase hexadecimal.
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Registry resolves the queries of requests that send the hash of their query instead of, or along with, the query.
+//
+// Queries registered by clients are kept in the store: a client first sends only the hash, and when the
+// query is not found, sends the query with its hash, which registers it. The queries of the allowlist,
+// loaded from a manifest, are always found. In strict mode, only the queries of the allowlist may run,
+// whether they are sent by hash or in full, and clients cannot register queries.
+type Registry struct {
+	store     Store
+	allowlist map[string]string
+	strict    bool
+}
+
+// NewRegistry creates a registry. store may be nil to disable the registration of queries by the clients,
+// and allowlist may be nil when there is no manifest. strict requires an allowlist.
+func NewRegistry(store Store, allowlist map[string]string, strict bool) *Registry {
+	return &Registry{store: store, allowlist: allowlist, strict: strict}
+}
+
+// Resolve returns the query a request runs. query is the query sent by the client, which may be empty,
+// and extension is its persistedQuery extension, which may be nil.
+func (r *Registry) Resolve(ctx context.Context, query string, extension *Extension) (string, error) {
+	if extension == nil {
+		if r.strict && !r.allowed(Hash(query)) {
+			return "", apperror.Forbidden(util.T(ctx, "persisted_query_not_allowed"))
+		}
+		return query, nil
+	}
+	if extension.Version != Version {
+		return "", apperror.New(CodeNotSupported, MessageNotSupported)
+	}
+	hash := strings.ToLower(extension.Sha256Hash)
+	if !validHash(hash) {
+		return "", apperror.Validation(util.T(ctx, "invalid_persisted_query_hash"))
+	}
+
+	// The client sent the query with its hash
+	if query != "" {
+		if Hash(query) != hash {
+			return "", apperror.Validation(util.T(ctx, "persisted_query_hash_mismatch"))
+		}
+		if r.strict && !r.allowed(hash) {
+			return "", apperror.Forbidden(util.T(ctx, "persisted_query_not_allowed"))
+		}
+		if r.store != nil && !r.allowed(hash) {
+			// The query runs even if it cannot be stored; the client will send it again
+			if err := r.store.Put(ctx, hash, query); err != nil {
+				log.Printf("[%s] Failed to store persisted query %s: %v", apperror.CorrelationID(ctx), hash, err)
+			}
+		}
+		return query, nil
+	}
+
+	// The client only sent the hash
+	if persistedQuery, ok := r.allowlist[hash]; ok {
+		return persistedQuery, nil
+	}
+	if r.strict {
+		return "", apperror.Forbidden(util.T(ctx, "persisted_query_not_allowed"))
+	}
+	if r.store == nil {
+		return "", apperror.New(CodeNotSupported, MessageNotSupported)
+	}
+	persistedQuery, found, err := r.store.Get(ctx, hash)
+	if err != nil {
+		return "", apperror.Internal(ctx, err, "internal_error")
+	}
+	if !found {
+		return "", apperror.New(CodeNotFound, MessageNotFound)
+	}
+	return persistedQuery, nil
+}
+
+// allowed tells whether a query is in the allowlist.
+func (r *Registry) allowed(hash string) bool {
+	_, ok := r.allowlist[hash]
+	return ok
+}
@@ -0,0 +1,87 @@
+package persisted
+
+import (
+	"context"
+	"graphqlapplication/apperror"
+	"strings"
+	"testing"
+)
+
+const (
+	allowedQuery = "{ items { id } }"
+	otherQuery   = "{ items { name } }"
+)
+
+func extension(query string) *Extension {
+	return &Extension{Version: Version, Sha256Hash: Hash(query)}
+}
+
+func TestResolve(t *testing.T) {
+	allowlist := map[string]string{Hash(allowedQuery): allowedQuery}
+	open := func() *Registry { return NewRegistry(NewMemoryStore(10), allowlist, false) }
+	strict := func() *Registry { return NewRegistry(NewMemoryStore(10), allowlist, true) }
+	withoutStore := func() *Registry { return NewRegistry(nil, nil, false) }
+
+	tests := []struct {
+		name      string
+		registry  func() *Registry
+		query     string
+		extension *Extension
+		want      string
+		code      string
+	}{
+		{"query without extension", open, otherQuery, nil, otherQuery, ""},
+		{"hash of the allowlist", open, "", extension(allowedQuery), allowedQuery, ""},
+		{"hash in upper case", open, "", &Extension{Version: Version, Sha256Hash: strings.ToUpper(Hash(allowedQuery))}, allowedQuery, ""},
+		{"unknown hash", open, "", extension(otherQuery), "", CodeNotFound},
+		{"query with its hash", open, otherQuery, extension(otherQuery), otherQuery, ""},
+		{"query with another hash", open, otherQuery, extension(allowedQuery), "", apperror.CodeValidation},
+		{"hash that is not SHA-256", open, "", &Extension{Version: Version, Sha256Hash: "abc"}, "", apperror.CodeValidation},
+		{"unsupported version", open, "", &Extension{Version: 2, Sha256Hash: Hash(allowedQuery)}, "", CodeNotSupported},
+		{"hash without store", withoutStore, "", extension(otherQuery), "", CodeNotSupported},
+		{"strict: query of the allowlist", strict, allowedQuery, nil, allowedQuery, ""},
+		{"strict: hash of the allowlist", strict, "", extension(allowedQuery), allowedQuery, ""},
+		{"strict: query with the hash of the allowlist", strict, allowedQuery, extension(allowedQuery), allowedQuery, ""},
+		{"strict: query outside the allowlist", strict, otherQuery, nil, "", apperror.CodeForbidden},
+		{"strict: hash outside the allowlist", strict, "", extension(otherQuery), "", apperror.CodeForbidden},
+		{"strict: query with its hash outside the allowlist", strict, otherQuery, extension(otherQuery), "", apperror.CodeForbidden},
+		{"strict: query of the allowlist with changed whitespace", strict, allowedQuery + " ", nil, "", apperror.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.registry().Resolve(context.Background(), tt.query, tt.extension)
+			if code := apperror.CodeOf(err); code != tt.code || (err != nil && tt.code == "") {
+				t.Fatalf("Resolve() error = %v, want code %q", err, tt.code)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRegistersQueries(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		strict bool
+		// registered tells whether the query can be sent by hash once it was sent in full.
+		registered bool
+	}{
+		{"open", false, true},
+		{"strict", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(10)
+			r := NewRegistry(store, map[string]string{Hash(allowedQuery): allowedQuery}, tt.strict)
+			r.Resolve(ctx, otherQuery, extension(otherQuery))
+
+			_, found, _ := store.Get(ctx, Hash(otherQuery))
+			got, err := r.Resolve(ctx, "", extension(otherQuery))
+			if found != tt.registered || (err == nil) != tt.registered {
+				t.Errorf("stored = %v, Resolve() = (%q, %v), want the query to be registered: %v", found, got, err, tt.registered)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package persisted
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"graphqlapplication/util"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store keeps the queries registered by the clients, indexed by their hash.
+// The registry only passes hashes that are SHA-256 hashes in lowercase hexadecimal.
+type Store interface {
+	// Get returns the query of a hash, and whether it was found.
+	Get(ctx context.Context, hash string) (string, bool, error)
+	// Put stores the query of a hash.
+	Put(ctx context.Context, hash string, query string) error
+}
+
+// MemoryStore keeps the most recently used queries in memory. Its queries are lost when the application stops.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// memoryEntry is a query of a MemoryStore.
+type memoryEntry struct {
+	hash  string
+	query string
+}
+
+// NewMemoryStore creates a store that keeps at most capacity queries, forgetting the least recently used ones.
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryStore{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the query of a hash and marks it as recently used.
+func (s *MemoryStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[hash]
+	if !ok {
+		return "", false, nil
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).query, true, nil
+}
+
+// Put stores the query of a hash, forgetting the least recently used query if the store is full.
+func (s *MemoryStore) Put(ctx context.Context, hash string, query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[hash]; ok {
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.entries[hash] = s.order.PushFront(&memoryEntry{hash: hash, query: query})
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).hash)
+	}
+	return nil
+}
+
+// FileStore keeps each query in a file of a directory, named after its hash.
+// The directory can be shared by several instances of the application.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store in a directory, which is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the path of the file of a hash.
+func (s *FileStore) path(hash string) (string, error) {
+	if !validHash(hash) {
+		return "", fmt.Errorf("invalid persisted query hash %q", hash)
+	}
+	return filepath.Join(s.dir, hash+".graphql"), nil
+}
+
+// Get reads the query of a hash from its file.
+func (s *FileStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	path, err := s.path(hash)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// Put writes the query of a hash to its file. The file is written under another name and then renamed,
+// so a query is never read while it is being written.
+func (s *FileStore) Put(ctx context.Context, hash string, query string) error {
+	path, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, hash+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(query); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// TableName is the table of SQLStore.
+const TableName = "persisted_query"
+
+// SQLStore keeps the queries in the persisted_query table, shared by every instance of the application.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore creates a store in the persisted_query table of a database.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Get reads the query of a hash from the table.
+func (s *SQLStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	var query string
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT query FROM %s WHERE hash = ?", TableName), hash).Scan(&query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return query, true, nil
+}
+
+// Put inserts the query of a hash into the table. A query that was inserted meanwhile by another request is kept.
+func (s *SQLStore) Put(ctx context.Context, hash string, query string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (hash, query, time_create) VALUES (?, ?, ?)", TableName), hash, query, util.AuditTime())
+	if err == nil {
+		return nil
+	}
+	if _, found, getErr := s.Get(ctx, hash); getErr == nil && found {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package persisted
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "queries"))
+	if err != nil {
+		t.Fatalf("NewFileStore() failed: %v", err)
+	}
+	stores := []struct {
+		name  string
+		store Store
+	}{
+		{"memory", NewMemoryStore(10)},
+		{"file", fileStore},
+	}
+	ctx := context.Background()
+	hash := Hash(otherQuery)
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, found, err := tt.store.Get(ctx, hash); found || err != nil {
+				t.Fatalf("Get() of an unknown hash = (%v, %v), want (false, nil)", found, err)
+			}
+			if err := tt.store.Put(ctx, hash, otherQuery); err != nil {
+				t.Fatalf("Put() failed: %v", err)
+			}
+			query, found, err := tt.store.Get(ctx, hash)
+			if query != otherQuery || !found || err != nil {
+				t.Errorf("Get() = (%q, %v, %v), want (%q, true, nil)", query, found, err, otherQuery)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreForgetsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+	s.Put(ctx, "a", "query a")
+	s.Put(ctx, "b", "query b")
+	s.Get(ctx, "a")
+	s.Put(ctx, "c", "query c")
+
+	tests := []struct {
+		hash  string
+		found bool
+	}{
+		{"a", true},
+		{"b", false},
+		{"c", true},
+	}
+	for _, tt := range tests {
+		if _, found, _ := s.Get(ctx, tt.hash); found != tt.found {
+			t.Errorf("Get(%q) found = %v, want %v", tt.hash, found, tt.found)
+		}
+	}
+}
+
+func TestFileStoreRejectsInvalidHashes(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() failed: %v", err)
+	}
+	for _, hash := range []string{"", "../query", Hash(otherQuery) + "/x", "ABC"} {
+		if err := s.Put(context.Background(), hash, otherQuery); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", hash)
+		}
+		if _, _, err := s.Get(context.Background(), hash); err == nil {
+			t.Errorf("Get(%q) succeeded, want an error", hash)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("the directory holds %d files, want none", len(entries))
+	}
+}
@@ -0,0 +1,39 @@
+package trash
+
+// ItemResolver contains a record in the trash.
+type ItemResolver struct {
+	trashID     string
+	entity      string
+	recordID    string
+	data        *string
+	adminDelete *string
+	ipDelete    *string
+	timeDelete  *string
+}
+
+// PageResolver contains a page of records in the trash.
+type PageResolver struct {
+	items      []*ItemResolver
+	total      int32
+	limit      int32
+	page       int32
+	totalPages int32
+	hasNext    bool
+	hasPrev    bool
+}
+
+func (r *ItemResolver) TrashId() string      { return r.trashID }
+func (r *ItemResolver) Entity() string       { return r.entity }
+func (r *ItemResolver) RecordId() string     { return r.recordID }
+func (r *ItemResolver) Data() *string        { return r.data }
+func (r *ItemResolver) AdminDelete() *string { return r.adminDelete }
+func (r *ItemResolver) IpDelete() *string    { return r.ipDelete }
+func (r *ItemResolver) TimeDelete() *string  { return r.timeDelete }
+
+func (r *PageResolver) Items() *[]*ItemResolver { return &r.items }
+func (r *PageResolver) Total() *int32           { return &r.total }
+func (r *PageResolver) Limit() *int32           { return &r.limit }
+func (r *PageResolver) Page() *int32            { return &r.page }
+func (r *PageResolver) TotalPages() *int32      { return &r.totalPages }
+func (r *PageResolver) HasNext() *bool          { return &r.hasNext }
+func (r *PageResolver) HasPrevious() *bool      { return &r.hasPrev }
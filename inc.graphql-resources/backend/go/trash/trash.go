@@ -0,0 +1,249 @@
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/constant"
+	"graphqlapplication/database"
+	"graphqlapplication/input"
+	"graphqlapplication/util"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName is the table deleted records are moved to.
+const TableName = "trash"
+
+// Required reports whether deleted admins are moved to the trash.
+// It is enabled by setting the TRASH_REQUIRED environment variable to "true".
+func Required() bool {
+	return os.Getenv("TRASH_REQUIRED") == "true"
+}
+
+// Move stores the data of a record in the trash, as JSON.
+// The caller deletes the record afterwards, with the same transaction.
+func Move(ctx context.Context, db database.Executor, entity string, recordID interface{}, data map[string]interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (trash_id, entity, record_id, data, admin_delete, ip_delete, time_delete) VALUES (?, ?, ?, ?, ?, ?, ?)", TableName)
+	_, err = db.ExecContext(ctx, query,
+		uuid.New().String(),
+		entity,
+		fmt.Sprint(recordID),
+		string(encoded),
+		util.AuditAdmin(ctx),
+		util.AuditIP(ctx),
+		util.AuditTime(),
+	)
+	return err
+}
+
+// MoveRow reads all the columns of a record and stores them in the trash.
+// It does nothing if the record does not exist.
+func MoveRow(ctx context.Context, db database.Executor, tableName string, primaryKey string, recordID interface{}) error {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", tableName, primaryKey)
+	rows, err := db.QueryContext(ctx, query, recordID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return rows.Err()
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	data := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		switch v := values[i].(type) {
+		case []byte:
+			data[column] = string(v)
+		case time.Time:
+			data[column] = v.Format(constant.DateTimeFormat)
+		default:
+			data[column] = v
+		}
+	}
+	rows.Close()
+	return Move(ctx, db, tableName, recordID, data)
+}
+
+// MoveAll stores the data of several records of a table in the trash and deletes them, with the same transaction.
+// data returns the data of a record, or nil if it was not read. Exactly the records stored in the trash are deleted,
+// so no record is deleted without a copy, even if other records started to match the filter of a bulk delete meanwhile.
+// The records are deleted in batches of batchSize. It returns the number of deleted records.
+func MoveAll[K any](ctx context.Context, db database.Executor, tableName string, primaryKey string, ids []K, data func(id K) map[string]interface{}, batchSize int) (int64, error) {
+	moved := make([]K, 0, len(ids))
+	for _, id := range ids {
+		record := data(id)
+		if record == nil {
+			continue
+		}
+		if err := Move(ctx, db, tableName, id, record); err != nil {
+			return 0, err
+		}
+		moved = append(moved, id)
+	}
+	return input.ExecForKeys(ctx, db, "DELETE FROM "+tableName, nil, primaryKey, moved, batchSize)
+}
+
+// Restore inserts the most recently trashed version of a record back into its table and removes it from the trash.
+// Only the columns of the entity listed in columns are restored.
+func Restore(ctx context.Context, db database.Executor, entity string, recordID interface{}, columns input.ColumnMap) error {
+	var trashID, encoded string
+	query := fmt.Sprintf("SELECT trash_id, data FROM %s WHERE entity = ? AND record_id = ? ORDER BY time_delete DESC", TableName)
+	rows, err := db.QueryContext(ctx, query, entity, fmt.Sprint(recordID))
+	if err != nil {
+		return err
+	}
+	found := rows.Next()
+	if found {
+		err = rows.Scan(&trashID, &encoded)
+	} else {
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return apperror.NotFound(util.T(ctx, "no_item_found_in_trash", entity, recordID))
+	}
+
+	// Numbers are kept as text, so large integers keep their precision
+	decoder := json.NewDecoder(strings.NewReader(encoded))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+	var fields []string
+	for column := range data {
+		if known[column] {
+			fields = append(fields, column)
+		}
+	}
+	sort.Strings(fields)
+	placeholders := make([]string, len(fields))
+	params := make([]interface{}, len(fields))
+	for i, column := range fields {
+		placeholders[i] = "?"
+		if n, ok := data[column].(json.Number); ok {
+			params[i] = n.String()
+		} else {
+			params[i] = data[column]
+		}
+	}
+
+	insertQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", entity, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	if _, err := db.ExecContext(ctx, insertQuery, params...); err != nil {
+		return apperror.Internal(ctx, err, "failed_to_restore_item", entity)
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE trash_id = ?", TableName), trashID)
+	return err
+}
+
+// Purge permanently removes the trashed versions of a record and returns how many were removed.
+func Purge(ctx context.Context, db database.Executor, entity string, recordID interface{}) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE entity = ? AND record_id = ?", TableName)
+	result, err := db.ExecContext(ctx, query, entity, fmt.Sprint(recordID))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Columns maps the fields of the TrashItem type to the columns of the trash table.
+var Columns = input.ColumnMap{
+	"trash_id":     "trash_id",
+	"record_id":    "record_id",
+	"admin_delete": "admin_delete",
+	"ip_delete":    "ip_delete",
+	"time_delete":  "time_delete",
+}
+
+// List fetches a page of the trashed records of an entity. Without orderBy, the most recently deleted come first.
+func List(ctx context.Context, db database.Executor, entity string, args input.ListArgs, dialect database.Dialect) (*PageResolver, error) {
+	query, err := input.BuildListQuery(ctx, args, Columns, dialect)
+	if err != nil {
+		return nil, err
+	}
+	whereSQL, params := input.WhereIn(query.Where, query.Params, "entity", []interface{}{entity})
+
+	var total int32
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", TableName, whereSQL)
+	if err := db.QueryRowContext(ctx, countQuery, params...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	orderSQL := query.Order
+	if orderSQL == "" {
+		orderSQL = "ORDER BY time_delete DESC, trash_id DESC"
+	}
+	pageSQL, pageParams := dialect.Paginate(orderSQL, query.Limit, query.Offset)
+	queryParams := append(params, pageParams...)
+	sqlQuery := fmt.Sprintf("SELECT trash_id, entity, record_id, data, admin_delete, ip_delete, time_delete FROM %s %s %s", TableName, whereSQL, pageSQL)
+	rows, err := db.QueryContext(ctx, sqlQuery, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*ItemResolver{}
+	for rows.Next() {
+		item := &ItemResolver{}
+		err := rows.Scan(
+			&item.trashID,
+			&item.entity,
+			&item.recordID,
+			&item.data,
+			&item.adminDelete,
+			&item.ipDelete,
+			&item.timeDelete,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	totalPages := int32(0)
+	if total > 0 && query.Limit > 0 {
+		totalPages = (total + query.Limit - 1) / query.Limit
+	}
+	return &PageResolver{
+		items:      items,
+		total:      total,
+		limit:      query.Limit,
+		page:       query.Page,
+		totalPages: totalPages,
+		hasNext:    query.Page < totalPages,
+		hasPrev:    query.Page > 1,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingExecutor records the statements it runs and reports every parameter of a statement as an affected row.
+type recordingExecutor struct {
+	queries []string
+	params  [][]interface{}
+}
+
+func (e *recordingExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.params = append(e.params, args)
+	return driverResult(len(args)), nil
+}
+
+func (e *recordingExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (e *recordingExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+// driverResult is the result of a statement that changed a given number of rows.
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestMoveAllDeletesTheRecordsCopiedToTheTrash(t *testing.T) {
+	records := map[string]map[string]interface{}{
+		"a": {"product_id": "a", "name": "first"},
+		"b": {"product_id": "b", "name": "second"},
+		"d": {"product_id": "d", "name": "fourth"},
+	}
+	ids := []string{"a", "b", "c", "d"}
+	db := &recordingExecutor{}
+	affected, err := MoveAll(context.Background(), db, "product", "product_id", ids, func(id string) map[string]interface{} { return records[id] }, 2)
+	if err != nil {
+		t.Fatalf("MoveAll() failed: %v", err)
+	}
+
+	var copied, deleted []interface{}
+	for i, query := range db.queries {
+		switch {
+		case strings.HasPrefix(query, "INSERT INTO "+TableName):
+			if entity := db.params[i][1]; entity != "product" {
+				t.Errorf("the trash copy is of entity %v, want product", entity)
+			}
+			copied = append(copied, db.params[i][2])
+		case strings.HasPrefix(query, "DELETE FROM product WHERE product_id IN ("):
+			deleted = append(deleted, db.params[i]...)
+		default:
+			t.Errorf("MoveAll() ran %q", query)
+		}
+	}
+	// The record without data was neither copied nor deleted.
+	want := []interface{}{"a", "b", "d"}
+	if !reflect.DeepEqual(copied, want) {
+		t.Errorf("records copied to the trash = %v, want %v", copied, want)
+	}
+	if !reflect.DeepEqual(deleted, want) {
+		t.Errorf("records deleted = %v, want %v", deleted, want)
+	}
+	if affected != int64(len(want)) {
+		t.Errorf("MoveAll() = %d, want %d", affected, len(want))
+	}
+}
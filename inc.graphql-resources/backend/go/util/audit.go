@@ -0,0 +1,26 @@
+package util
+
+import (
+	"context"
+	"graphqlapplication/constant"
+	"time"
+)
+
+// AuditTime returns the current time in the format stored in the time_create and time_edit columns.
+func AuditTime() string {
+	return time.Now().Format(constant.DateTimeFormat)
+}
+
+// AuditAdmin returns the ID of the logged-in admin, stored in the context by the GraphQL middleware,
+// for the admin_create and admin_edit columns. It returns an empty string for anonymous requests.
+func AuditAdmin(ctx context.Context) string {
+	adminID, _ := ctx.Value(constant.SessionAdminId).(string)
+	return adminID
+}
+
+// AuditIP returns the IP address of the client, stored in the context by the GraphQL middleware,
+// for the ip_create and ip_edit columns.
+func AuditIP(ctx context.Context) string {
+	ip, _ := ctx.Value(constant.RemoteAddr).(string)
+	return ip
+}
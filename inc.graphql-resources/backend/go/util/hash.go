@@ -9,6 +9,7 @@ import (
 )
 
 // DoubleSha1 calculates sha1(sha1(password)).
+// It is only used to verify legacy password hashes; new hashes are created with HashPassword.
 func DoubleSha1(input string) string {
 	h1 := sha1.New()
 	h1.Write([]byte(input))
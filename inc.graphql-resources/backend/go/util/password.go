@@ -0,0 +1,206 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes and verifies admin passwords.
+// Encoded hashes carry their own parameters so that they can be verified
+// after the configuration has changed.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the given password.
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash.
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash reports whether the encoded hash was produced by another
+	// algorithm or with different parameters than this hasher uses.
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher hashes passwords with bcrypt.
+type BcryptHasher struct {
+	Cost int
+}
+
+// Argon2idHasher hashes passwords with argon2id and encodes them in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32 // In KiB
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// legacySha1Pattern matches the sha1(sha1(password)) hashes stored by MagicAppBuilder.
+var legacySha1Pattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+var errInvalidHash = errors.New("invalid password hash format")
+
+var passwordHasher PasswordHasher = NewArgon2idHasher()
+
+// NewBcryptHasher creates a BcryptHasher with the default cost.
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{Cost: bcrypt.DefaultCost}
+}
+
+// NewArgon2idHasher creates an Argon2idHasher with the parameters recommended by RFC 9106.
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 2,
+		KeyLen:  32,
+		SaltLen: 16,
+	}
+}
+
+// InitPasswordHasher selects the algorithm used for new password hashes.
+// Supported values are "argon2id" (default) and "bcrypt".
+func InitPasswordHasher(algorithm string) {
+	switch strings.ToLower(algorithm) {
+	case "bcrypt":
+		passwordHasher = NewBcryptHasher()
+	case "argon2id", "":
+		passwordHasher = NewArgon2idHasher()
+	default:
+		log.Printf("Unknown PASSWORD_HASH_ALGORITHM '%s', using 'argon2id'", algorithm)
+		passwordHasher = NewArgon2idHasher()
+	}
+}
+
+// SetPasswordHasher replaces the hasher used for new password hashes.
+func SetPasswordHasher(h PasswordHasher) {
+	passwordHasher = h
+}
+
+// HashPassword hashes a password with the configured hasher.
+func HashPassword(password string) (string, error) {
+	return passwordHasher.Hash(password)
+}
+
+// VerifyPassword checks a password against a stored hash of any supported format,
+// including legacy sha1(sha1(password)) hashes.
+// needsRehash is true when the password matches but the stored hash should be
+// replaced with one produced by the configured hasher.
+func VerifyPassword(password, encoded string) (ok bool, needsRehash bool) {
+	var err error
+	switch {
+	case legacySha1Pattern.MatchString(encoded):
+		ok = subtle.ConstantTimeCompare([]byte(DoubleSha1(password)), []byte(strings.ToLower(encoded))) == 1
+		return ok, ok
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		ok, err = NewArgon2idHasher().Verify(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$"):
+		ok, err = NewBcryptHasher().Verify(password, encoded)
+	default:
+		return false, false
+	}
+	if err != nil {
+		log.Printf("Password verification error: %v", err)
+		return false, false
+	}
+	return ok, ok && passwordHasher.NeedsRehash(encoded)
+}
+
+// Hash returns the bcrypt hash of the password.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether the password matches the bcrypt hash.
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NeedsRehash reports whether the hash is not a bcrypt hash with the configured cost.
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Hash returns the argon2id hash of the password in PHC string format.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify reports whether the password matches the argon2id hash,
+// using the parameters encoded in the hash.
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// NeedsRehash reports whether the hash is not an argon2id hash with the configured parameters.
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Time != h.Time || params.Memory != h.Memory || params.Threads != h.Threads ||
+		uint32(len(key)) != h.KeyLen || uint32(len(salt)) != h.SaltLen
+}
+
+// decodeArgon2id parses a PHC formatted argon2id hash.
+func decodeArgon2id(encoded string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version: %d", version)
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+	params.SaltLen = uint32(len(salt))
+	params.KeyLen = uint32(len(key))
+	return params, salt, key, nil
+}
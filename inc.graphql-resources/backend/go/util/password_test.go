@@ -0,0 +1,102 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2id returns an argon2id hasher with small parameters, so the tests run quickly.
+func fastArgon2id() *Argon2idHasher {
+	return &Argon2idHasher{Time: 1, Memory: 64, Threads: 1, KeyLen: 16, SaltLen: 8}
+}
+
+// useHasher sets the hasher for new hashes for the duration of the test.
+func useHasher(t *testing.T, h PasswordHasher) {
+	t.Helper()
+	previous := passwordHasher
+	SetPasswordHasher(h)
+	t.Cleanup(func() { SetPasswordHasher(previous) })
+}
+
+func mustHash(t *testing.T, h PasswordHasher, password string) string {
+	t.Helper()
+	encoded, err := h.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash(%q) failed: %v", password, err)
+	}
+	return encoded
+}
+
+func TestVerifyPassword(t *testing.T) {
+	argon := fastArgon2id()
+	bcryptHasher := &BcryptHasher{Cost: bcrypt.MinCost}
+	argonHash := mustHash(t, argon, "secret")
+	bcryptHash := mustHash(t, bcryptHasher, "secret")
+	legacyHash := DoubleSha1("secret")
+
+	tests := []struct {
+		name        string
+		hasher      PasswordHasher
+		password    string
+		encoded     string
+		ok          bool
+		needsRehash bool
+	}{
+		{"argon2id with the configured parameters", argon, "secret", argonHash, true, false},
+		{"argon2id with a wrong password", argon, "wrong", argonHash, false, false},
+		{"argon2id with other parameters", &Argon2idHasher{Time: 2, Memory: 64, Threads: 1, KeyLen: 16, SaltLen: 8}, "secret", argonHash, true, true},
+		{"bcrypt while argon2id is configured", argon, "secret", bcryptHash, true, true},
+		{"bcrypt with the configured cost", bcryptHasher, "secret", bcryptHash, true, false},
+		{"bcrypt with a wrong password", bcryptHasher, "wrong", bcryptHash, false, false},
+		{"legacy sha1", argon, "secret", legacyHash, true, true},
+		{"legacy sha1 in upper case", argon, "secret", strings.ToUpper(legacyHash), true, true},
+		{"legacy sha1 with a wrong password", argon, "wrong", legacyHash, false, false},
+		{"unknown format", argon, "secret", "secret", false, false},
+		{"malformed argon2id", argon, "secret", "$argon2id$v=19$m=64", false, false},
+		{"empty hash", argon, "secret", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useHasher(t, tt.hasher)
+			ok, needsRehash := VerifyPassword(tt.password, tt.encoded)
+			if ok != tt.ok || needsRehash != tt.needsRehash {
+				t.Errorf("VerifyPassword() = (%v, %v), want (%v, %v)", ok, needsRehash, tt.ok, tt.needsRehash)
+			}
+		})
+	}
+}
+
+func TestHashPasswordUsesConfiguredHasher(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{"argon2id", fastArgon2id(), "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"bcrypt", &BcryptHasher{Cost: bcrypt.MinCost}, "$2a$04$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useHasher(t, tt.hasher)
+			encoded, err := HashPassword("secret")
+			if err != nil {
+				t.Fatalf("HashPassword() failed: %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("HashPassword() = %q, want prefix %q", encoded, tt.prefix)
+			}
+			if ok, needsRehash := VerifyPassword("secret", encoded); !ok || needsRehash {
+				t.Errorf("VerifyPassword() of a new hash = (%v, %v), want (true, false)", ok, needsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idHashIsSalted(t *testing.T) {
+	h := fastArgon2id()
+	if mustHash(t, h, "secret") == mustHash(t, h, "secret") {
+		t.Error("two hashes of the same password are equal")
+	}
+}
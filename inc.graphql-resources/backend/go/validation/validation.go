@@ -0,0 +1,222 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graphqlapplication/apperror"
+	"graphqlapplication/util"
+	"log"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Formats supported by Rule.Format.
+const (
+	FormatEmail = "email"
+	FormatURL   = "url"
+)
+
+// Rule holds the constraints of a field of an input type.
+// Zero values mean no constraint.
+type Rule struct {
+	Field     string
+	Required  bool
+	MinLength int
+	MaxLength int
+	Min       *float64
+	Max       *float64
+	Format    string
+	Pattern   string
+}
+
+// Rules holds the rules of the fields of an input type.
+type Rules []Rule
+
+// FieldError describes why the value of a field was refused.
+// Rule is the name of the failed constraint, e.g. "required" or "maxLength".
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Error is returned when one or more fields of an input are invalid.
+type Error struct {
+	message string
+	fields  []FieldError
+}
+
+func (e *Error) Error() string { return e.message }
+
+// Fields returns the errors of the invalid fields.
+func (e *Error) Fields() []FieldError { return e.fields }
+
+// Extensions adds the code and the errors of the fields to the GraphQL error,
+// so clients can show each message next to its field.
+func (e *Error) Extensions() map[string]interface{} {
+	fields := make([]map[string]interface{}, len(e.fields))
+	for i, f := range e.fields {
+		fields[i] = map[string]interface{}{
+			"field":   f.Field,
+			"rule":    f.Rule,
+			"message": f.Message,
+		}
+	}
+	return map[string]interface{}{
+		"code":   apperror.CodeValidation,
+		"fields": fields,
+	}
+}
+
+// Float returns a pointer to a number, for the Min and Max of a rule.
+func Float(n float64) *float64 { return &n }
+
+// Validate checks the values of the fields of an input against their rules.
+// values maps the fields to their values, which may be pointers; a nil value means the field is not set.
+// When partial is true, as for updates, required fields may be left unset.
+// It returns an *Error listing every invalid field, or nil.
+func Validate(ctx context.Context, rules Rules, values map[string]interface{}, partial bool) error {
+	var fields []FieldError
+	for _, rule := range rules {
+		value, set := deref(values[rule.Field])
+		if !set {
+			if rule.Required && !partial {
+				fields = append(fields, FieldError{Field: rule.Field, Rule: "required", Message: util.T(ctx, "field_is_required", rule.Field)})
+			}
+			continue
+		}
+		if fieldError := rule.check(ctx, value); fieldError != nil {
+			fields = append(fields, *fieldError)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return &Error{message: util.T(ctx, "invalid_input", strings.Join(messages, "; ")), fields: fields}
+}
+
+// At prefixes the fields of a validation error with the path of the input in a list,
+// e.g. "name" becomes "inputs.2.name". Other errors are returned unchanged.
+func At(err error, path string, index int) error {
+	var validationError *Error
+	if !errors.As(err, &validationError) {
+		return err
+	}
+	fields := make([]FieldError, len(validationError.fields))
+	for i, f := range validationError.fields {
+		f.Field = fmt.Sprintf("%s.%d.%s", path, index, f.Field)
+		fields[i] = f
+	}
+	return &Error{message: validationError.message, fields: fields}
+}
+
+// check returns the error of the first constraint of the rule that the value breaks, or nil.
+func (rule Rule) check(ctx context.Context, value interface{}) *FieldError {
+	fail := func(name string, key string, args ...interface{}) *FieldError {
+		return &FieldError{Field: rule.Field, Rule: name, Message: util.T(ctx, key, append([]interface{}{rule.Field}, args...)...)}
+	}
+
+	if s, ok := value.(string); ok {
+		if rule.Required && strings.TrimSpace(s) == "" {
+			return fail("required", "field_is_required")
+		}
+		length := utf8.RuneCountInString(s)
+		if rule.MinLength > 0 && length < rule.MinLength {
+			return fail("minLength", "field_too_short", rule.MinLength)
+		}
+		if rule.MaxLength > 0 && length > rule.MaxLength {
+			return fail("maxLength", "field_too_long", rule.MaxLength)
+		}
+		if s == "" {
+			return nil
+		}
+		switch rule.Format {
+		case FormatEmail:
+			if address, err := mail.ParseAddress(s); err != nil || address.Address != s {
+				return fail("email", "field_invalid_email")
+			}
+		case FormatURL:
+			if u, err := url.ParseRequestURI(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fail("url", "field_invalid_url")
+			}
+		}
+		if rule.Pattern != "" {
+			if re := pattern(rule.Pattern); re != nil && !re.MatchString(s) {
+				return fail("pattern", "field_invalid_format")
+			}
+		}
+		return nil
+	}
+
+	if n, ok := number(value); ok {
+		if rule.Min != nil && n < *rule.Min {
+			return fail("min", "field_too_small", strconv.FormatFloat(*rule.Min, 'f', -1, 64))
+		}
+		if rule.Max != nil && n > *rule.Max {
+			return fail("max", "field_too_large", strconv.FormatFloat(*rule.Max, 'f', -1, 64))
+		}
+	}
+	return nil
+}
+
+// deref returns the value a pointer points to, and whether the value is set.
+func deref(value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		return v.Elem().Interface(), true
+	}
+	return value, true
+}
+
+// number converts the numeric values of the input types to float64, including the input.Decimal scalar.
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case interface{ Float64() (float64, error) }:
+		f, err := n.Float64()
+		return f, err == nil
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// patterns caches the compiled patterns of the rules.
+var patterns sync.Map
+
+// pattern returns the compiled form of a pattern.
+// An invalid pattern is logged once and returns nil, so the constraint is skipped.
+func pattern(expr string) *regexp.Regexp {
+	if re, ok := patterns.Load(expr); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Printf("Invalid validation pattern %q: %v", expr, err)
+	}
+	patterns.Store(expr, re)
+	return re
+}
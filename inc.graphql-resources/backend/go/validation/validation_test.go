@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"graphqlapplication/apperror"
+	"graphqlapplication/input"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// failures returns the field and the rule of each field error of a validation error, e.g. "name:required".
+func failures(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var validationError *Error
+	if !errors.As(err, &validationError) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	var list []string
+	for _, f := range validationError.Fields() {
+		list = append(list, f.Field+":"+f.Rule)
+	}
+	return list
+}
+
+func TestValidate(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	int32Ptr := func(n int32) *int32 { return &n }
+	decimal := func(s string) *input.Decimal {
+		d := &input.Decimal{}
+		if err := d.UnmarshalGraphQL(s); err != nil {
+			t.Fatalf("UnmarshalGraphQL(%q) failed: %v", s, err)
+		}
+		return d
+	}
+	rules := Rules{
+		{Field: "name", Required: true, MinLength: 2, MaxLength: 4},
+		{Field: "email", Format: FormatEmail},
+		{Field: "website", Format: FormatURL},
+		{Field: "code", Pattern: "^[A-Z]+$"},
+		{Field: "quantity", Min: Float(1), Max: Float(10)},
+		{Field: "price", Min: Float(0.5)},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		partial bool
+		want    []string
+	}{
+		{"valid input", map[string]interface{}{"name": strPtr("abc"), "quantity": int32Ptr(5)}, false, nil},
+		{"required field not set", map[string]interface{}{}, false, []string{"name:required"}},
+		{"required field set to nil", map[string]interface{}{"name": (*string)(nil)}, false, []string{"name:required"}},
+		{"required field not set in a partial input", map[string]interface{}{}, true, nil},
+		{"required field set to blanks in a partial input", map[string]interface{}{"name": strPtr("  ")}, true, []string{"name:required"}},
+		{"length counted in characters, not bytes", map[string]interface{}{"name": strPtr("éèêë")}, false, nil},
+		{"too short", map[string]interface{}{"name": strPtr("é")}, false, []string{"name:minLength"}},
+		{"too long", map[string]interface{}{"name": strPtr("abcde")}, false, []string{"name:maxLength"}},
+		{"too small", map[string]interface{}{"name": "abc", "quantity": int32(0)}, false, []string{"quantity:min"}},
+		{"too large", map[string]interface{}{"name": "abc", "quantity": int64(11)}, false, []string{"quantity:max"}},
+		{"decimal within the bounds", map[string]interface{}{"name": "abc", "price": decimal("0.50")}, false, nil},
+		{"decimal too small", map[string]interface{}{"name": "abc", "price": decimal("0.49")}, false, []string{"price:min"}},
+		{"float too small", map[string]interface{}{"name": "abc", "price": 0.1}, false, []string{"price:min"}},
+		{"email", map[string]interface{}{"name": "abc", "email": "admin@example.com"}, false, nil},
+		{"email with a name", map[string]interface{}{"name": "abc", "email": "Admin <admin@example.com>"}, false, []string{"email:email"}},
+		{"email without a domain", map[string]interface{}{"name": "abc", "email": "admin"}, false, []string{"email:email"}},
+		{"empty email", map[string]interface{}{"name": "abc", "email": ""}, false, nil},
+		{"url", map[string]interface{}{"name": "abc", "website": "https://example.com/a?b=c"}, false, nil},
+		{"url without a host", map[string]interface{}{"name": "abc", "website": "https:///a"}, false, []string{"website:url"}},
+		{"url with another scheme", map[string]interface{}{"name": "abc", "website": "javascript://example.com"}, false, []string{"website:url"}},
+		{"relative url", map[string]interface{}{"name": "abc", "website": "/a"}, false, []string{"website:url"}},
+		{"pattern", map[string]interface{}{"name": "abc", "code": "ABC"}, false, nil},
+		{"pattern not matched", map[string]interface{}{"name": "abc", "code": "abc"}, false, []string{"code:pattern"}},
+		{
+			"every invalid field is listed",
+			map[string]interface{}{"email": "admin", "quantity": 20},
+			false,
+			[]string{"name:required", "email:email", "quantity:max"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(context.Background(), rules, tt.values, tt.partial)
+			if got := failures(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+			if err != nil && apperror.CodeOf(err) != apperror.CodeValidation {
+				t.Errorf("Validate() error code = %q, want %q", apperror.CodeOf(err), apperror.CodeValidation)
+			}
+		})
+	}
+}
+
+func TestPatternCache(t *testing.T) {
+	re := pattern("^[0-9]+$")
+	if re == nil || !re.MatchString("123") {
+		t.Fatalf("pattern() = %v, want a compiled pattern", re)
+	}
+	if again := pattern("^[0-9]+$"); again != re {
+		t.Errorf("pattern() compiled the pattern again")
+	}
+
+	// An invalid pattern is cached as nil and its constraint is skipped.
+	if re := pattern("[0-9"); re != nil {
+		t.Errorf("pattern() of an invalid pattern = %v, want nil", re)
+	}
+	if cached, ok := patterns.Load("[0-9"); !ok || cached.(*regexp.Regexp) != nil {
+		t.Errorf("the invalid pattern is not cached")
+	}
+	rules := Rules{{Field: "code", Pattern: "[0-9"}}
+	if err := Validate(context.Background(), rules, map[string]interface{}{"code": "abc"}, false); err != nil {
+		t.Errorf("Validate() with an invalid pattern = %v, want nil", err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	err := Validate(context.Background(), Rules{{Field: "name", Required: true}}, map[string]interface{}{}, false)
+	if got, want := failures(t, At(err, "inputs", 2)), []string{"inputs.2.name:required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("At() = %v, want %v", got, want)
+	}
+	if got := failures(t, err); !reflect.DeepEqual(got, []string{"name:required"}) {
+		t.Errorf("At() changed the original error to %v", got)
+	}
+
+	other := apperror.Forbidden("forbidden")
+	if got := At(other, "inputs", 0); got != error(other) {
+		t.Errorf("At() of another error = %v, want it unchanged", got)
+	}
+	if got := At(nil, "inputs", 0); got != nil {
+		t.Errorf("At(nil) = %v, want nil", got)
+	}
+}
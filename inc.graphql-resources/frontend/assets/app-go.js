@@ -292,6 +292,35 @@ document.addEventListener('DOMContentLoaded', () => {
         }
     }
 
+    graphqlApp.pages['admin-permission'] = {
+        url: 'admin-permission',
+        title: 'admin_permission', // The translation key for the page title.
+        method: 'GET',
+        headers: {
+            'X-Requested-with': 'xmlhttprequest',
+            'X-Language-Id': graphqlApp.languageId,
+            'Accept-Language': graphqlApp.languageId
+        },
+        accept: 'text/html',
+        // Callback function executed on a successful fetch.
+        success: (data, container, dom) => {
+            // Hide standard entity view elements.
+            dom.filterContainer.style.display = 'none';
+            dom.paginationContainer.style.display = 'none';
+            dom.filterContainer.innerHTML = '';
+            dom.tableDataContainer.innerHTML = '';
+            // Inject the fetched HTML into the main content container.
+            container.innerHTML = data;
+        },
+        // Callback function for handling errors.
+        error: (errorCode, errorMessage, container, dom) => {
+            console.error(errorCode, errorMessage);
+        },
+        render: (data, container, dom) => {
+            // Not used here as content is fetched via URL.
+        }
+    };
+
     graphqlApp.pages['update-password'] = {
         url: 'update-password',
         title: 'update_password', // The translation key for the page title.
@@ -575,6 +604,39 @@ function handleAdminSearch(event) {
     window.location.hash = newHash;
 }
 
+function handlePermissionLevelChange(adminLevelId) {
+    window.location.hash = `#admin-permission?adminLevelId=${encodeURIComponent(adminLevelId)}`;
+}
+
+async function handlePermissionSave(event) {
+    event.preventDefault();
+    const form = document.getElementById('admin-permission-form');
+    const formData = new FormData(form);
+
+    try {
+        const response = await fetch('admin-permission', {
+            method: 'POST',
+            body: formData,
+            headers: {
+                'X-Requested-With': 'xmlhttprequest',
+                'Accept': 'application/json',
+                'X-Language-Id': graphqlApp.languageId,
+                'Accept-Language': graphqlApp.languageId
+            }
+        });
+        const result = await response.json();
+        if (result.success) {
+            await graphqlApp.customAlert({ title: graphqlApp.t('success'), message: result.message });
+            graphqlApp.handleRouteChange();
+        } else {
+            await graphqlApp.customAlert({ title: graphqlApp.t('error'), message: result.message });
+        }
+    } catch (error) {
+        console.error('Error saving permissions:', error);
+        await graphqlApp.customAlert({ title: graphqlApp.t('error'), message: graphqlApp.t('unexpected_error_occurred') });
+    }
+}
+
 async function handleMessageDelete(messageId) {
     const confirmed = await graphqlApp.customConfirm({
         title: graphqlApp.t('confirmation_title'),
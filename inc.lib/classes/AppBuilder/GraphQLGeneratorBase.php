@@ -127,7 +127,10 @@ class GraphQLGeneratorBase
                     'isAutoIncrement' => $column['autoIncrement'],
                     'isForeignKey' => false,
                     'references' => null,
-                    'primaryKeyValue' => isset($column['primaryKeyValue']) ? $column['primaryKeyValue'] : null
+                    'primaryKeyValue' => isset($column['primaryKeyValue']) ? $column['primaryKeyValue'] : null,
+                    'nullable' => !isset($column['nullable']) || ($column['nullable'] && $column['nullable'] !== 'false'),
+                    'default' => isset($column['default']) ? $column['default'] : null,
+                    'validation' => isset($column['validation']) && is_array($column['validation']) ? $column['validation'] : array()
                 );
                 if ($columnName === $this->activeField) {
                     $this->analyzedSchema[$tableName]['hasActiveColumn'] = true;
@@ -53,6 +53,7 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
 
     /**
      * Maps a database type to a Go type.
+     * Dates, times, decimals and JSON are mapped to the custom scalars of the input package.
      *
      * @param string $dbType The database column type (e.g., VARCHAR, INT, TIMESTAMP).
      * @param int|null $length The length of the column.
@@ -61,16 +62,22 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
     private function mapDbTypeToGoType($dbType, $length = null) // NOSONAR
     {
         $dbType = strtolower($dbType);
+        if (strpos($dbType, 'json') !== false) {
+            return 'input.JSON';
+        }
         if (strpos($dbType, 'varchar') !== false || strpos($dbType, 'text') !== false) {
             return 'string';
         }
         if (strpos($dbType, 'timestamp') !== false || strpos($dbType, 'datetime') !== false) {
-            return 'time.Time';
+            return 'input.DateTime';
         }
         if (strpos($dbType, 'date') !== false) {
-            return 'time.Time';
+            return 'input.Date';
+        }
+        if (strpos($dbType, 'decimal') !== false || strpos($dbType, 'numeric') !== false) {
+            return 'input.Decimal';
         }
-        if (strpos($dbType, 'decimal') !== false || strpos($dbType, 'float') !== false || strpos($dbType, 'double') !== false) {
+        if (strpos($dbType, 'float') !== false || strpos($dbType, 'double') !== false) {
             return 'float64';
         }
         if ((strpos($dbType, 'tinyint') !== false && isset($length) && $length == '1') || strpos($dbType, 'bool') !== false || strpos($dbType, 'bit') !== false) {
@@ -101,7 +108,10 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
         if (strpos($dbType, 'date') !== false) {
             return 'string';
         }
-        if (strpos($dbType, 'decimal') !== false || strpos($dbType, 'float') !== false || strpos($dbType, 'double') !== false) {
+        if (strpos($dbType, 'decimal') !== false || strpos($dbType, 'numeric') !== false) {
+            return 'string'; // Kept as text, so no precision is lost
+        }
+        if (strpos($dbType, 'float') !== false || strpos($dbType, 'double') !== false) {
             return 'float64';
         }
         if ((strpos($dbType, 'tinyint') !== false && isset($length) && $length == '1') || strpos($dbType, 'bool') !== false || strpos($dbType, 'bit') !== false) {
@@ -113,6 +123,24 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
         return 'string'; // Default fallback
     }
 
+    /**
+     * Maps a column to the Go type of its field in the GraphQL API.
+     * Dates, times, decimals and JSON are exposed as the custom scalars of the input package, e.g. input.DateTime,
+     * and the other columns with the type of their model field.
+     * Primary keys keep the type of their model field, as it is also the type of the IDs of the queries and the loaders.
+     *
+     * @param array $col The column definition.
+     * @return string The corresponding Go type string.
+     */
+    private function mapColumnToApiType($col)
+    {
+        $goType = $this->mapDbTypeToGoType($col['type'], $col['length']);
+        if ($col['isPrimaryKey'] || strpos($goType, 'input.') !== 0) {
+            return $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']);
+        }
+        return $goType;
+    }
+
     /**
      * Main function to generate all files for the Go project.
      *
@@ -169,6 +197,7 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
     {
         $types1 = [];
         $types2 = [];
+        $types4 = [];
         foreach ($this->analyzedSchema as $tableName => $tableInfo) {
             $pascalName = $this->pascalCase($tableName);
 
@@ -182,27 +211,67 @@ class GraphQLGeneratorGo extends GraphQLGeneratorBase // NOSONAR
                 }
             }
             $types1[] = "\t{$pascalName}(ctx context.Context, args struct{ ID $pkType }) (*{$pascalName}Resolver, error)";
+            $types1[] = "\tload{$pascalName}(ctx context.Context, id $pkType) (*{$pascalName}Resolver, error)";
+            foreach($tableInfo['columns'] as $columnName => $col)
+            {
+                if($col['isForeignKey'])
+                {
+                    $fkType = $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']);
+                    $types1[] = "\tload" . $this->pluralize($pascalName) . "By" . $this->goName($columnName) . "(ctx context.Context, id $fkType, args input.ListArgs) (*{$pascalName}PageResolver, error)";
+                }
+            }
             $types2[] = "\t*{$pascalName}QueryResolver";
             $types3[] = "\troot.{$pascalName}QueryResolver = New{$pascalName}QueryResolver(root)";
+            $types4[] = "\t\"{$tableName}\",";
         }
         $code1 = implode("\r\n", $types1);
         $code2 = implode("\r\n", $types2);
         $code3 = implode("\r\n", $types3);
+        $code4 = implode("\r\n", $types4);
+        $packageName = $this->projectConfig['moduleName'];
         return <<<GO
 package resolver
 
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"{$packageName}/audit"
+	"{$packageName}/auth"
+	"{$packageName}/config"
+	"{$packageName}/database"
+	"{$packageName}/input"
 )
 
+// EntityNames lists the tables exposed by the GraphQL API.
+// They are the entities that can be granted to admin levels.
+var EntityNames = []string{
+$code4
+	audit.TableName,
+}
+
 type ResolverRoot interface {
-	DBConnection() *sql.DB
+	DBConnection(ctx context.Context) database.Executor
+	BeginTx(ctx context.Context) (*database.Tx, error)
 $code1
 }
 
-func (r *RootResolver) DBConnection() *sql.DB {
-	return r.db
+// DBConnection returns the transaction of the request if there is one, or the database otherwise.
+func (r *RootResolver) DBConnection(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+// BeginTx starts a transaction, or joins the transaction of the request if there is one.
+func (r *RootResolver) BeginTx(ctx context.Context) (*database.Tx, error) {
+	return database.BeginTx(ctx, r.db)
+}
+
+// AuditLogs fetches a page of the audit trail of all entities.
+func (r *RootResolver) AuditLogs(ctx context.Context, args input.ListArgs) (*audit.PageResolver, error) {
+	if err := auth.Authorize(ctx, r.DBConnection(ctx), audit.TableName, auth.ActionList); err != nil {
+		return nil, err
+	}
+	return audit.Logs(ctx, r.DBConnection(ctx), args, config.Dialect)
 }
 
 type RootResolver struct {
@@ -215,6 +284,38 @@ func NewRootResolver(db *sql.DB) *RootResolver {
 $code3
     return root
 }
+
+// PageInfoResolver describes the page of a connection, as defined by the Relay specification.
+type PageInfoResolver struct {
+	hasNext     bool
+	hasPrevious bool
+	startCursor *string
+	endCursor   *string
+}
+
+func (r *PageInfoResolver) HasNextPage() bool     { return r.hasNext }
+func (r *PageInfoResolver) HasPreviousPage() bool { return r.hasPrevious }
+func (r *PageInfoResolver) StartCursor() *string  { return r.startCursor }
+func (r *PageInfoResolver) EndCursor() *string    { return r.endCursor }
+
+// BulkResultResolver contains the result of a bulk mutation.
+type BulkResultResolver struct {
+	affected int32
+	ids      []string
+}
+
+func (r *BulkResultResolver) Affected() int32 { return r.affected }
+func (r *BulkResultResolver) Ids() []string   { return r.ids }
+
+// newBulkResultResolver creates the result of a bulk mutation from the primary keys of the records it selected
+// and the number of records the database changed.
+func newBulkResultResolver[K any](ids []K, affected int64) *BulkResultResolver {
+	result := &BulkResultResolver{affected: int32(affected), ids: make([]string, len(ids))}
+	for i, id := range ids {
+		result.ids[i] = fmt.Sprint(id)
+	}
+	return result
+}
 GO;
     }
 
@@ -272,6 +373,8 @@ GO;
      * Imports are determined dynamically based on:
      * - Project module name
      * - Whether the table uses UUID auto-generation
+     * - Whether deleted records are moved to the trash
+     * - Whether the table has a version column
      * - Whether PostgreSQL-specific behavior may be needed
      *
      * @param array $tableInfo Metadata describing the table structure.
@@ -284,15 +387,29 @@ GO;
         $libraries = [];
         $libraries[] = "\t\"context\"";
         $libraries[] = "\t\"database/sql\"";
-        $libraries[] = "\t\"errors\"";
         $libraries[] = "\t\"fmt\"";
+        $libraries[] = "\t\"{$packageName}/aggregate\"";
+        $libraries[] = "\t\"{$packageName}/apperror\"";
+        $libraries[] = "\t\"{$packageName}/audit\"";
+        $libraries[] = "\t\"{$packageName}/auth\"";
+        if($this->getVersionColumn($tableInfo) !== null)
+        {
+            $libraries[] = "\t\"{$packageName}/concurrency\"";
+        }
         $libraries[] = "\t\"{$packageName}/config\"";
-        $libraries[] = "\t\"{$packageName}/constant\"";
+        $libraries[] = "\t\"{$packageName}/database\"";
+        $libraries[] = "\t\"{$packageName}/event\"";
         $libraries[] = "\t\"{$packageName}/input\"";
+        $libraries[] = "\t\"{$packageName}/loader\"";
         $libraries[] = "\t\"{$packageName}/model\"";
+        if($this->isTrashEnabled($tableInfo['name']))
+        {
+            $libraries[] = "\t\"{$packageName}/trash\"";
+        }
         $libraries[] = "\t\"{$packageName}/util\"";
+        $libraries[] = "\t\"{$packageName}/validation\"";
+        $libraries[] = "\t\"slices\"";
         $libraries[] = "\t\"strings\"";
-        $libraries[] = "\t\"time\"";
 
         $autogenerated = false;
         foreach ($tableInfo['columns'] as $col) {
@@ -349,6 +466,27 @@ type {$resolverName}PageResolver struct {
 	hasNext    bool
 	hasPrev    bool
 }
+
+// {$resolverName}ConnectionResolver contains a page of {$resolverNamePlural} with Relay-style cursors.
+type {$resolverName}ConnectionResolver struct {
+	edges    []*{$resolverName}EdgeResolver
+	pageInfo *PageInfoResolver
+	query    input.ConnectionQuery
+	r        *{$resolverName}QueryResolver
+}
+
+// {$resolverName}EdgeResolver contains a single {$resolverName} of a connection and its cursor.
+type {$resolverName}EdgeResolver struct {
+	cursor string
+	node   *{$resolverName}Resolver
+}
+
+// {$resolverName}ChangeResolver contains a change to a {$resolverName}, sent to the subscribers of its changes.
+type {$resolverName}ChangeResolver struct {
+	action string
+	id     string
+	item   *{$resolverName}Resolver
+}
 ";
         return implode("\r\n", $contents);
     }
@@ -422,6 +560,7 @@ type {$resolverName}PageResolver struct {
      * - Relationship resolvers for foreign keys
      * - A single-item query (fetch by primary key)
      * - A paginated list query with filtering and sorting
+     * - A subscription to the changes of the entity
      *
      * Pagination, filtering, and sorting are delegated to the query builder,
      * while this resolver focuses on translating the data into model objects.
@@ -452,21 +591,34 @@ type {$resolverName}PageResolver struct {
             if($col['isPrimaryKey'] && empty($pkName))
             {
                 $pkName = $columnName;
+                $pkGoName = $this->goName($this->pascalCase($columnName));
                 $pkType = $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']);
             }
             $columnInfo[] = [
                 //"name" => ucfirst($columnName),
                 "name" => $this->pascalCase($columnName),
                 "type" => $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']),
+                "apiType" => $this->mapColumnToApiType($col),
                 "pointer" => $col['isPrimaryKey']
             ];
             $columNames[] = $columnName;
         }
-        foreach($columnInfo as $info)
+        $cursorCases = [];
+        foreach($columnInfo as $index => $info)
         {
             $goName = $this->goName($this->pascalCase($info['name']));
-            $methods[] = sprintf("func (r *$singleResolver) %s() *%s { return %sr.m.$goName }", $info['name'], $info['type'], $info['pointer'] ? '&' : '');
+            if($info['apiType'] !== $info['type'])
+            {
+                // The scalar is built from the text read from the database, e.g. input.DateFromDB
+                $methods[] = sprintf("func (r *$singleResolver) %s() (*%s, error) { return %sFromDB(r.m.$goName) }", $info['name'], $info['apiType'], $info['apiType']);
+            }
+            else
+            {
+                $methods[] = sprintf("func (r *$singleResolver) %s() *%s { return %sr.m.$goName }", $info['name'], $info['type'], $info['pointer'] ? '&' : '');
+            }
+            $cursorCases[] = "\tcase \"{$columNames[$index]}\":\r\n\t\treturn r.m.$goName";
         }
+        $cursorValueCases = implode("\r\n", $cursorCases);
         $methods = $this->prettifyMethods($methods, "{ return");
         $entityMethods = implode("\r\n", $methods);
 
@@ -478,7 +630,6 @@ type {$resolverName}PageResolver struct {
         {
             if($col['isForeignKey'])
             {
-                $colType = $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']);
                 $relEntity = $col['references'];
                 $pascalEntityName = $this->pascalCase($relEntity);
                 $fkName = $this->goName($this->pascalCase($columnName));
@@ -488,7 +639,7 @@ func (r *$singleResolver) {$pascalEntityName}(ctx context.Context) (*{$pascalEnt
 	if r.m.{$fkName} == nil {
 		return nil, nil
 	}
-	return r.r.root.$pascalEntityName(ctx, struct{ ID $colType } { ID: *r.m.{$fkName} })
+	return r.r.root.load{$pascalEntityName}(ctx, *r.m.{$fkName})
 }
 ";
                 
@@ -504,6 +655,192 @@ func (r *$singleResolver) {$pascalEntityName}(ctx context.Context) (*{$pascalEnt
         $addresOfColumns2 = implode("\r\n", $addCols);
 
         $columnList = implode(", ", $columNames);
+
+        // GraphQL fields that can be used to filter and sort, mapped to their columns
+        $columnMapName = $this->camelCase($tableName) . "Columns";
+        $columnMapEntries = [];
+        foreach($columNames as $columnName)
+        {
+            $columnMapEntries[] = sprintf("\t%-" . ($maxLength + 3) . "s\"%s\",", "\"{$columnName}\":", $columnName);
+        }
+        $columnMap = implode("\r\n", $columnMapEntries);
+
+        // Numeric fields, which can be summed and averaged
+        $numericMapName = $this->camelCase($tableName) . "NumericColumns";
+        $numericMapEntries = [];
+        foreach($tableInfo['columns'] as $columnName => $col)
+        {
+            $goType = $this->mapDbTypeToGoType($col['type'], $col['length']);
+            if(in_array($goType, ['int32', 'int64', 'float64', 'input.Decimal']))
+            {
+                $numericMapEntries[] = sprintf("\t%-" . ($maxLength + 3) . "s\"%s\",", "\"{$columnName}\":", $columnName);
+            }
+        }
+        $numericMap = implode("\r\n", $numericMapEntries);
+
+        // Child collections of the tables that refer to this table, e.g. category.products
+        foreach($this->getReverseRelations($tableName) as $relation)
+        {
+            $childName = $relation['table'];
+            $childPascalName = $this->pascalCase($childName);
+            $childFieldName = ucfirst($this->pluralize($this->camelCase($childName)));
+            $loadMethod = "load" . $this->pluralize($childPascalName) . "By" . $this->goName($relation['column']);
+            $relationMethods .= "
+// {$childFieldName} resolves a page of the $childName records that refer to this $pascalName.
+func (r *$singleResolver) {$childFieldName}(ctx context.Context, args input.ListArgs) (*{$childPascalName}PageResolver, error) {
+	return r.r.root.{$loadMethod}(ctx, r.m.{$pkGoName}, args)
+}
+";
+        }
+
+        // Batched loaders of the pages requested through the child collections of the referenced tables
+        $fkLoaders = [];
+        foreach($tableInfo['columns'] as $columnName => $col)
+        {
+            if(!$col['isForeignKey'])
+            {
+                continue;
+            }
+            $fkType = $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']);
+            $fkGoName = $this->goName($columnName);
+            $loadMethod = "load{$pascalNamePlural}By{$fkGoName}";
+            $findMethod = "find{$pascalName}PagesBy{$fkGoName}";
+            $fkLoaders[] = <<<GO
+
+// {$loadMethod} fetches a page of the {$tableName} records whose {$columnName} is the given ID.
+// Pages requested with the same arguments while resolving one request are fetched together.
+func (r *{$pascalName}QueryResolver) {$loadMethod}(ctx context.Context, id $fkType, args input.ListArgs) (*{$pageResolver}, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+	query, err := input.BuildListQuery(ctx, args, {$columnMapName}, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	fetch := func(ctx context.Context, ids []$fkType) (map[$fkType]*{$pageResolver}, error) {
+		return r.{$findMethod}(ctx, ids, query)
+	}
+	return loader.Get(ctx, "{$tableName}.{$columnName} "+query.Key(), fetch).Load(ctx, id)
+}
+
+// {$findMethod} fetches a page of {$tableName} records for each of the given {$columnName} values without checking permissions.
+// The rows of each page are numbered with ROW_NUMBER(), so all pages are fetched with one query.
+func (r *{$pascalName}QueryResolver) {$findMethod}(ctx context.Context, ids []$fkType, query input.ListQuery) (map[$fkType]*{$pageResolver}, error) {
+
+	tableName := "{$tableName}"
+	columns := "$columnList"
+	primaryKey := "{$pkName}"
+	foreignKey := "{$columnName}"
+
+	keys := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = id
+	}
+	whereSQL, params := input.WhereIn(query.Where, query.Params, foreignKey, keys)
+
+	// Count the items of each page
+	totals := make(map[$fkType]int32, len(ids))
+	countQuery := fmt.Sprintf("SELECT %s, COUNT(*) FROM %s %s GROUP BY %s", foreignKey, tableName, whereSQL, foreignKey)
+	countRows, err := r.root.DBConnection(ctx).QueryContext(ctx, countQuery, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer countRows.Close()
+	for countRows.Next() {
+		var key $fkType
+		var total int32
+		if err := countRows.Scan(&key, &total); err != nil {
+			return nil, err
+		}
+		totals[key] = total
+	}
+	if err := countRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Fetch the items of each page
+	orderSQL := query.Order
+	if orderSQL == "" {
+		orderSQL = "ORDER BY " + primaryKey
+	}
+	queryParams := append(params, query.Offset, query.Offset+query.Limit)
+	queryQuery := fmt.Sprintf("SELECT %s FROM (SELECT %s, ROW_NUMBER() OVER (PARTITION BY %s %s) AS row_num FROM %s %s) t WHERE row_num > ? AND row_num <= ? ORDER BY row_num", columns, columns, foreignKey, orderSQL, tableName, whereSQL)
+	rows, err := r.root.DBConnection(ctx).QueryContext(ctx, queryQuery, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[$fkType][]*$singleResolver, len(ids))
+	for rows.Next() {
+		m := model.{$pascalName}{}
+		err := rows.Scan(
+$addresOfColumns2
+		)
+		if err != nil {
+			return nil, err
+		}
+		key := *m.{$fkGoName}
+		items[key] = append(items[key], &$singleResolver{m: &m, r: r})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pages := make(map[$fkType]*{$pageResolver}, len(ids))
+	for _, id := range ids {
+		pageItems := items[id]
+		if pageItems == nil {
+			pageItems = []*$singleResolver{}
+		}
+		pages[id] = new{$pageResolver}(pageItems, totals[id], query.Limit, query.Page)
+	}
+	return pages, nil
+}
+GO;
+        }
+        $foreignKeyLoaders = implode("", $fkLoaders);
+
+        $versionColumn = $this->getVersionColumn($tableInfo);
+        if($versionColumn !== null)
+        {
+            $rowVersion = <<<GO
+
+// RowVersion returns the current version of this $pascalName, the value of its {$versionColumn} column.
+// Update mutations accept it as expectedVersion.
+func (r *$singleResolver) RowVersion() *string {
+	return concurrency.Version(r.cursorValue("{$versionColumn}"))
+}
+
+GO;
+        }
+        else
+        {
+            $rowVersion = <<<GO
+
+// RowVersion always returns nil, as the {$tableName} table has no version column.
+func (r *$singleResolver) RowVersion() *string {
+	return nil
+}
+
+GO;
+        }
+
+        $trashQuery = "";
+        if($this->isTrashEnabled($tableName))
+        {
+            $trashQuery = <<<GO
+
+// Trash{$pascalNamePlural} fetches a page of the deleted {$pascalNamePlural} kept in the trash, most recently deleted first.
+func (r *{$pascalName}QueryResolver) Trash{$pascalNamePlural}(ctx context.Context, args input.ListArgs) (*trash.PageResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+	return trash.List(ctx, r.root.DBConnection(ctx), "{$tableName}", args, config.Dialect)
+}
+
+GO;
+        }
         
         $listMethods = <<<GO
 func (r *{$pageResolver}) Items() *[]*$singleResolver { return &r.items }
@@ -517,8 +854,33 @@ GO;
         $listMethodsArr = explode("\n", $listMethods);
         $listMethodsArr = $this->prettifyMethods($listMethodsArr, "{ return");
         $listMethods = implode("\n", $listMethodsArr);
+
+        $connectionMethods = $this->prettifyMethods([
+            "func (r *{$pascalName}ConnectionResolver) Edges() *[]*{$pascalName}EdgeResolver { return &r.edges }",
+            "func (r *{$pascalName}ConnectionResolver) PageInfo() *PageInfoResolver { return r.pageInfo }",
+            "func (r *{$pascalName}EdgeResolver) Cursor() string { return r.cursor }",
+            "func (r *{$pascalName}EdgeResolver) Node() *$singleResolver { return r.node }"
+        ], "{ return");
+        $connectionMethods = implode("\r\n", $connectionMethods);
+
+        $changeMethods = $this->prettifyMethods([
+            "func (r *{$pascalName}ChangeResolver) Action() string { return r.action }",
+            "func (r *{$pascalName}ChangeResolver) ID() string { return r.id }",
+            "func (r *{$pascalName}ChangeResolver) {$pascalName}() *$singleResolver { return r.item }"
+        ], "{ return");
+        $changeMethods = implode("\r\n", $changeMethods);
         
         return <<<GO
+// {$columnMapName} maps the GraphQL fields of {$pascalName} that can be used to filter and sort to their columns.
+var {$columnMapName} = input.ColumnMap{
+$columnMap
+}
+
+// {$numericMapName} maps the numeric fields of {$pascalName}, which can be summed and averaged, to their columns.
+var {$numericMapName} = input.ColumnMap{
+$numericMap
+}
+
 // New{$pascalName}QueryResolver creates a new resolver for {$pascalName} queries.
 func New{$pascalName}QueryResolver(root ResolverRoot) *{$pascalName}QueryResolver {
 	return &{$pascalName}QueryResolver{root: root}
@@ -527,18 +889,57 @@ func New{$pascalName}QueryResolver(root ResolverRoot) *{$pascalName}QueryResolve
 // Getter methods for $pascalName properties
 $entityMethods
 $relationMethods
+// cursorValue returns the value of a column of this $pascalName, used to build connection cursors.
+func (r *$singleResolver) cursorValue(column string) interface{} {
+	switch column {
+$cursorValueCases
+	}
+	return nil
+}
+
+// auditSnapshot returns the values of the columns of this $pascalName for the audit trail, or nil for a nil resolver.
+func (r *$singleResolver) auditSnapshot() audit.Snapshot {
+	if r == nil {
+		return nil
+	}
+	return audit.NewSnapshot({$columnMapName}, r.cursorValue)
+}
+$rowVersion
 // Methods for $pascalNamePlural
 $listMethods
 
+// Methods for {$pascalName} connections
+$connectionMethods
+
+// TotalCount counts the {$pascalNamePlural} matching the filters of the connection.
+// It is only called, and the count only runs, when the field is requested.
+func (r *{$pascalName}ConnectionResolver) TotalCount(ctx context.Context) (*int32, error) {
+	var total int32
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", "{$tableName}", r.query.Where)
+	err := r.r.root.DBConnection(ctx).QueryRowContext(ctx, countQuery, r.query.Params...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
 // {$pascalName} fetches a single {$tableName} by its ID.
 func (r *{$pascalName}QueryResolver) {$pascalName}(ctx context.Context, args struct{ ID $pkType }) (*$singleResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDetail); err != nil {
+		return nil, err
+	}
+	return r.find{$pascalName}(ctx, args.ID)
+}
+
+// find{$pascalName} fetches a single {$tableName} by its ID without checking permissions.
+func (r *{$pascalName}QueryResolver) find{$pascalName}(ctx context.Context, id $pkType) (*$singleResolver, error) {
 	
 	tableName := "{$tableName}"
 	columns := "$columnList"
 	primaryKey := "{$pkName}"
 	
 	sqlQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", columns, tableName, primaryKey)
-	row := r.root.DBConnection().QueryRowContext(ctx, sqlQuery, args.ID)
+	row := r.root.DBConnection(ctx).QueryRowContext(ctx, sqlQuery, id)
 	m := model.{$pascalName}{}
 	err := row.Scan(
 $addresOfColumns1
@@ -552,43 +953,119 @@ $addresOfColumns1
 	return &$singleResolver{m: &m, r: r}, nil
 }
 
-// {$pascalNamePlural} fetches a paginated list of {$pascalNamePlural}.
-func (r *{$pascalName}QueryResolver) {$pascalNamePlural}(ctx context.Context, args struct {
-	Limit   *int32
-	Offset  *int32
-	Page    *int32
-	Size    *int32
-	OrderBy *[]*input.SortInput
-	Filter  *[]*input.FilterInput
-}) (*{$pageResolver}, error) {
+// load{$pascalName} fetches a single {$tableName} by its ID for a relation field.
+// Lookups made while resolving the same request are collected into one query and cached.
+func (r *{$pascalName}QueryResolver) load{$pascalName}(ctx context.Context, id $pkType) (*$singleResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDetail); err != nil {
+		return nil, err
+	}
+	return loader.Get(ctx, "{$tableName}", r.find{$pascalNamePlural}ByID).Load(ctx, id)
+}
+
+// find{$pascalNamePlural}ByID fetches the {$tableName} records with the given IDs without checking permissions.
+func (r *{$pascalName}QueryResolver) find{$pascalNamePlural}ByID(ctx context.Context, ids []$pkType) (map[$pkType]*$singleResolver, error) {
 
 	tableName := "{$tableName}"
 	columns := "$columnList"
+	primaryKey := "{$pkName}"
 
-	// Pagination
-	limit, page, offset := input.GetPagination(input.PaginationArgs{
-		Limit:  args.Limit,
-		Offset: args.Offset,
-		Page:   args.Page,
-		Size:   args.Size,
-	})
+	placeholders := make([]string, len(ids))
+	params := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		params[i] = id
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)", columns, tableName, primaryKey, strings.Join(placeholders, ", "))
+	rows, err := r.root.DBConnection(ctx).QueryContext(ctx, sqlQuery, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[$pkType]*$singleResolver, len(ids))
+	for rows.Next() {
+		m := model.{$pascalName}{}
+		err := rows.Scan(
+$addresOfColumns2
+		)
+		if err != nil {
+			return nil, err
+		}
+		items[m.{$pkGoName}] = &$singleResolver{m: &m, r: r}
+	}
+	return items, rows.Err()
+}
 
-	// Build query
+// {$pascalNamePlural} fetches a paginated list of {$pascalNamePlural}.
+func (r *{$pascalName}QueryResolver) {$pascalNamePlural}(ctx context.Context, args input.ListArgs) (*{$pageResolver}, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+	columns := "$columnList"
 
-	whereSQL, orderSQL, params := input.BuildQuery(args.Filter, args.OrderBy, config.IsPostgres)
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", tableName, whereSQL)
+	// Build query with filters, sorting and pagination
+	query, err := input.BuildListQuery(ctx, args, {$columnMapName}, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", tableName, query.Where)
 
 	// Count total items
 	var total int32
-	err := r.root.DBConnection().QueryRowContext(ctx, countQuery, params...).Scan(&total)
+	err = r.root.DBConnection(ctx).QueryRowContext(ctx, countQuery, query.Params...).Scan(&total)
 	if err != nil {
 		return nil, err
 	}
 
 	// Fetch items
-	queryParams := append(params, limit, offset)
-	queryQuery := fmt.Sprintf("SELECT %s FROM %s %s %s LIMIT ? OFFSET ?", columns, tableName, whereSQL, orderSQL)
-	rows, err := r.root.DBConnection().QueryContext(ctx, queryQuery, queryParams...)
+	pageSQL, pageParams := config.Dialect.Paginate(query.Order, query.Limit, query.Offset)
+	queryParams := append(query.Params, pageParams...)
+	queryQuery := fmt.Sprintf("SELECT %s FROM %s %s %s", columns, tableName, query.Where, pageSQL)
+	rows, err := r.root.DBConnection(ctx).QueryContext(ctx, queryQuery, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*$singleResolver{}
+	for rows.Next() {
+		m := model.{$pascalName}{}
+		err := rows.Scan(
+$addresOfColumns2
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &$singleResolver{m: &m, r: r})
+	}
+
+	return new{$pageResolver}(items, total, query.Limit, query.Page), nil
+}
+
+// {$pascalNamePlural}Connection fetches a page of {$pascalNamePlural} after or before a cursor.
+// Unlike {$pascalNamePlural}, it pages through keyset conditions instead of an offset and does not count the items.
+func (r *{$pascalName}QueryResolver) {$pascalNamePlural}Connection(ctx context.Context, args input.ConnectionArgs) (*{$pascalName}ConnectionResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+	columns := "$columnList"
+	primaryKey := "{$pkName}"
+
+	query, err := input.BuildConnectionQuery(ctx, args, {$columnMapName}, primaryKey, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one more item than requested to know whether there are more pages
+	pageSQL, pageParams := config.Dialect.Paginate(query.Order, query.Size+1, 0)
+	queryParams := append(query.PageParams, pageParams...)
+	queryQuery := fmt.Sprintf("SELECT %s FROM %s %s %s", columns, tableName, query.PageWhere, pageSQL)
+	rows, err := r.root.DBConnection(ctx).QueryContext(ctx, queryQuery, queryParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -605,7 +1082,99 @@ $addresOfColumns2
 		}
 		items = append(items, &$singleResolver{m: &m, r: r})
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hasMore := int32(len(items)) > query.Size
+	if hasMore {
+		items = items[:query.Size]
+	}
+	if query.Backward {
+		slices.Reverse(items)
+	}
+
+	edges := make([]*{$pascalName}EdgeResolver, len(items))
+	for i, item := range items {
+		edges[i] = &{$pascalName}EdgeResolver{cursor: query.Cursor(item.cursorValue), node: item}
+	}
+	pageInfo := &PageInfoResolver{}
+	pageInfo.hasNext, pageInfo.hasPrevious = query.PageInfo(hasMore)
+	if len(edges) > 0 {
+		pageInfo.startCursor = &edges[0].cursor
+		pageInfo.endCursor = &edges[len(edges)-1].cursor
+	}
+
+	return &{$pascalName}ConnectionResolver{edges: edges, pageInfo: pageInfo, query: query, r: r}, nil
+}
+
+// {$pascalName}Aggregate computes metrics over the {$pascalNamePlural} matching the filters, grouped by the groupBy fields.
+func (r *{$pascalName}QueryResolver) {$pascalName}Aggregate(ctx context.Context, args aggregate.Args) ([]*aggregate.BucketResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+	return aggregate.Query(ctx, r.root.DBConnection(ctx), "{$tableName}", args, {$columnMapName}, {$numericMapName}, config.Dialect)
+}
+
+// {$pascalName}History fetches a page of the audit entries of a {$tableName}, most recent first.
+func (r *{$pascalName}QueryResolver) {$pascalName}History(ctx context.Context, args struct {
+	ID      $pkType
+	Limit   *int32
+	Offset  *int32
+	Page    *int32
+	OrderBy *[]*input.SortInput
+	Filter  *[]*input.FilterInput
+}) (*audit.PageResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDetail); err != nil {
+		return nil, err
+	}
+	listArgs := input.ListArgs{Limit: args.Limit, Offset: args.Offset, Page: args.Page, OrderBy: args.OrderBy, Filter: args.Filter}
+	return audit.History(ctx, r.root.DBConnection(ctx), "{$tableName}", args.ID, listArgs, config.Dialect)
+}
+$trashQuery
+// {$pascalName}Changed streams the changes to the {$pascalNamePlural} matching the filter, once they are committed.
+// Deleted records are matched and sent with their values before the deletion.
+func (r *{$pascalName}QueryResolver) {$pascalName}Changed(ctx context.Context, args struct{ Filter *[]*input.FilterInput }) (<-chan *{$pascalName}ChangeResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionList); err != nil {
+		return nil, err
+	}
+	// Refuse unknown fields and operators now rather than never sending a change
+	if _, _, _, err := input.BuildQuery(ctx, args.Filter, nil, {$columnMapName}, config.Dialect); err != nil {
+		return nil, err
+	}
+
+	events := event.Subscribe(ctx, "{$tableName}")
+	changes := make(chan *{$pascalName}ChangeResolver)
+	go func() {
+		defer close(changes)
+		for e := range events {
+			item, ok := e.Record.(*$singleResolver)
+			if !ok || !input.Match(args.Filter, e.Values, {$columnMapName}) {
+				continue
+			}
+			select {
+			case changes <- &{$pascalName}ChangeResolver{action: e.Action, id: e.ID, item: item}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// Methods for {$pascalName} changes
+$changeMethods
+
+// publish{$pascalName}Change publishes a change to a {$tableName} to the subscribers of its changes.
+func publish{$pascalName}Change(ctx context.Context, action string, item *$singleResolver) {
+	if item == nil {
+		return
+	}
+	event.Publish(ctx, event.Event{Entity: "{$tableName}", Action: action, ID: fmt.Sprint(item.m.{$pkGoName}), Values: item.auditSnapshot(), Record: item})
+}
 
+// new{$pageResolver} creates a page of {$pascalNamePlural} and computes the number of pages.
+func new{$pageResolver}(items []*$singleResolver, total, limit, page int32) *{$pageResolver} {
 	totalPages := int32(0)
 	if total > 0 {
 		totalPages = (total + limit - 1) / limit
@@ -619,8 +1188,9 @@ $addresOfColumns2
 		totalPages: totalPages,
 		hasNext:    page < totalPages,
 		hasPrev:    page > 1,
-	}, nil
+	}
 }
+$foreignKeyLoaders
 GO;
     }
 
@@ -674,7 +1244,6 @@ GO;
         $colInfo = [];
         $maxLength = 1;
         $pkType = '';
-        $getLastId = '';
         foreach($tableInfo['columns'] as $columnName => $col)
         {
             if($col['isPrimaryKey'] && empty($pkName))
@@ -686,7 +1255,8 @@ GO;
             $colInfo[] = [
                 'name' => $this->goName($this->pascalCase($columnName)),
                 'columnName' => $columnName,
-                'type' => $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length'])
+                'type' => $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']),
+                'apiType' => $this->mapColumnToApiType($col)
             ];
             if($maxLength < strlen($columnName))
             {
@@ -702,6 +1272,7 @@ GO;
         $par = [];
         $paramInsert = [];
         $backendHandledColumnNames = $this->getBackendHandledColumnNames();
+        $versionColumn = $this->getVersionColumn($tableInfo);
         $autogeneratedCol = '';
         $primaryKeyCol = '';
         $primaryKeyValue = '';
@@ -720,7 +1291,7 @@ GO;
                 $autogeneratedCol = $colName;
                 continue;
             }
-            if(in_array($colName, $backendHandledColumnNames))
+            if(in_array($colName, $backendHandledColumnNames) || $colName === $versionColumn)
             {
                 continue;
             }
@@ -760,68 +1331,93 @@ GO;
             {
                 array_push($columnToInsert, $v['columnName']);
                 array_push($placeholderUpdate, "?");
-                array_push($par, "\tparams = append(params, time.Now().Format(constant.DateTimeFormat))"); 
+                array_push($par, "\tparams = append(params, util.AuditTime())"); 
                 if($k == 'timeEdit')
                 {
                     $updateCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, time.Now().Format(constant.DateTimeFormat))
+    params = append(params, util.AuditTime())
 
 GO;
                     $toggleCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, time.Now().Format(constant.DateTimeFormat))
+    params = append(params, util.AuditTime())
 
 GO;
                 }
-                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, time.Now().Format(constant.DateTimeFormat))\r\n";
+                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, util.AuditTime())\r\n";
             }
             if(($k == 'ipCreate' || $k == 'ipEdit') && in_array($v['columnName'], $entityColumns))
             {
                 array_push($columnToInsert, $v['columnName']);
                 array_push($placeholderUpdate, "?");
-                array_push($par, "\tparams = append(params, ctx.Value(constant.RemoteAddr).(string))"); 
+                array_push($par, "\tparams = append(params, util.AuditIP(ctx))"); 
                 if($k == 'ipEdit')
                 {
                     $updateCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, ctx.Value(constant.RemoteAddr).(string))
+    params = append(params, util.AuditIP(ctx))
 
 GO;
                     $toggleCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, ctx.Value(constant.RemoteAddr).(string))
+    params = append(params, util.AuditIP(ctx))
 
 GO;
                 }
-                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, ctx.Value(constant.RemoteAddr).(string))\r\n";
+                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, util.AuditIP(ctx))\r\n";
             }
             if(($k == 'adminCreate' || $k == 'adminEdit') && in_array($v['columnName'], $entityColumns))
             {
                 array_push($columnToInsert, $v['columnName']);
                 array_push($placeholderUpdate, "?");
-                array_push($par, "\tparams = append(params, ctx.Value(constant.SessionAdminId).(string))"); 
+                array_push($par, "\tparams = append(params, util.AuditAdmin(ctx))"); 
                 if($k == 'adminEdit')
                 {
                     $updateCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, ctx.Value(constant.SessionAdminId).(string))
+    params = append(params, util.AuditAdmin(ctx))
 
 GO;
                     $toggleCode[] = <<<GO
     fields = append(fields, "{$v['columnName']} = ?")
-    params = append(params, ctx.Value(constant.SessionAdminId).(string))
+    params = append(params, util.AuditAdmin(ctx))
 
 GO;
                 }
-                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, ctx.Value(constant.SessionAdminId).(string))\r\n";
+                $paramInsert[] = "\tfields = append(fields, \"{$v['columnName']}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, util.AuditAdmin(ctx))\r\n";
             }
         }
 
+        if($versionColumn === 'version')
+        {
+            // The version starts at 1 and is incremented by every change
+            array_push($columnToInsert, $versionColumn);
+            array_push($placeholderUpdate, "?");
+            array_push($par, "\tparams = append(params, 1)");
+            $paramInsert[] = "\tfields = append(fields, \"{$versionColumn}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, 1)\r\n";
+            $updateCode[] = <<<GO
+    fields = append(fields, "{$versionColumn} = {$versionColumn} + 1")
+
+GO;
+            $toggleCode[] = <<<GO
+    fields = append(fields, "{$versionColumn} = {$versionColumn} + 1")
+
+GO;
+        }
+
         $tp[] = "{$this->activeField} = ?";
 
         $uuid = "";
         $goCol = $this->goName($this->camelCase($primaryKeyCol));
+        $pkZero = $pkType == 'string' ? '""' : '0';
+        $insertCode = <<<GO
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		return $pkZero, apperror.Internal(ctx, err, "failed_to_create_item", tableName)
+	}
+	return id, nil
+GO;
         if($autogenerated)
         {
             array_unshift($columnToInsert, $autogeneratedCol);
@@ -831,21 +1427,33 @@ GO;
         }
         else if($autoincrement)
         {
-            $getLastId = "	var insertId int64
-	insertId, err = result.LastInsertId()
-    if err != nil {
-        return nil, errors.New(util.T(ctx, \"failed_to_get_last_insert_id\", err))
-    } else {
-		id = fmt.Sprintf(\"%v\", insertId)
+            $insertedId = $pkType == 'string' ? "fmt.Sprintf(\"%d\", id)" : "{$pkType}(id)";
+            $insertCode = <<<GO
+	var id int64
+	if returningQuery, ok := config.Dialect.InsertReturning(tableName, strings.Join(fields, ", "), strings.Join(placeholders, ", "), "{$primaryKeyCol}"); ok {
+		// The database does not support LastInsertId, so the new key is returned by the INSERT itself
+		err := db.QueryRowContext(ctx, returningQuery, params...).Scan(&id)
+		if err != nil {
+			return $pkZero, apperror.Internal(ctx, err, "failed_to_create_item", tableName)
+		}
+	} else {
+		result, err := db.ExecContext(ctx, query, params...)
+		if err != nil {
+			return $pkZero, apperror.Internal(ctx, err, "failed_to_create_item", tableName)
+		}
+		id, err = result.LastInsertId()
+		if err != nil {
+			return $pkZero, apperror.Internal(ctx, err, "failed_to_get_last_insert_id")
+		}
 	}
-";
+	return $insertedId, nil
+GO;
         }
         else
         {
-            $getLastId = "";
-            $uuid = "\r\n\tid := \"\"
-    if args.Input.{$goCol} != nil {
-        id = *args.Input.{$goCol}
+            $uuid = "\r\n\tvar id $pkType
+    if in.{$goCol} != nil {
+        id = *in.{$goCol}
     }";
         }
 
@@ -853,36 +1461,180 @@ GO;
         {
             array_unshift($paramInsert, "\tfields = append(fields, \"{$primaryKeyCol}\")\r\n\tplaceholders = append(placeholders, \"?\")\r\n\tparams = append(params, *args.Input.$goCol)\r\n");
 
-            $returnUpdateCodes = "    if args.Input.$goCol != nil && *args.Input.$goCol != args.ID {
+            $returnUpdateCodes = "	id := args.ID
+	if args.Input.$goCol != nil && *args.Input.$goCol != args.ID {
 		query = fmt.Sprintf(\"UPDATE %s SET %s = ? WHERE %s = ?\", tableName, primaryKey, primaryKey)
-		_, err = r.root.DBConnection().ExecContext(ctx, query, args.Input.$goCol, args.ID)
+		_, err = tx.ExecContext(ctx, query, args.Input.$goCol, args.ID)
 		if err != nil {
-			return nil, errors.New(util.T(ctx, \"failed_to_update_item\", tableName, err))
+			return nil, apperror.Internal(ctx, err, \"failed_to_update_item\", tableName)
 		}
-		return r.$pascalName(ctx, struct{ ID $pkType }{ID: *args.Input.$goCol})
-	} else {
-		return r.$pascalName(ctx, struct{ ID $pkType }{ID: args.ID})
+		id = *args.Input.$goCol
 	}";
         }
         else
         {
-            $returnUpdateCodes = "    return r.{$pascalName}(ctx, struct{ ID $pkType }{ID: args.ID})";
+            $returnUpdateCodes = "	id := args.ID";
         }
 
-        $paramSet = implode("\r\n", $paramInsert);
-        $updateCodes = implode("\r\n", $updateCode);
+        // The insert and update helpers read the input from "in"
+        $paramSet = str_replace("args.Input.", "in.", implode("\r\n", $paramInsert));
+        $updateCodes = str_replace("args.Input.", "in.", implode("\r\n", $updateCode));
+        $pascalNamePlural = $this->pluralize($pascalName);
+        $columnMapName = $this->camelCase($tableName) . "Columns";
         $toggleCodes = implode("\r\n", $toggleCode);
 
+        $expectedVersionArg = "";
+        $versionCheck = "";
+        $versionVerify = "";
+        $updateResult = "_, err =";
+        if($versionColumn !== null)
+        {
+            $expectedVersionArg = "\r\n\tExpectedVersion *string";
+            $updateResult = "result, err :=";
+            $versionCheck = <<<GO
+	if args.ExpectedVersion != nil {
+		// Refuse to overwrite a {$tableName} that was changed since the client read it
+		if before != nil && !concurrency.Matches(before.RowVersion(), *args.ExpectedVersion) {
+			return nil, concurrency.NewConflictError(ctx, tableName, args.ID, before.RowVersion())
+		}
+		condition, conditionParams := concurrency.Condition("{$versionColumn}", *args.ExpectedVersion)
+		query += " AND " + condition
+		params = append(params, conditionParams...)
+	}
+
+GO;
+            $versionVerify = <<<GO
+	if args.ExpectedVersion != nil {
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			// Changed by someone else between the read and the update, unless nothing was changed at all
+			if err := concurrency.Verify(ctx, tx, tableName, primaryKey, args.ID, "{$versionColumn}", *args.ExpectedVersion); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+GO;
+        }
+
+        $trashMove = "";
+        $deleteAll = <<<GO
+	// Delete the selected records rather than those matching the filter now, so every deletion has its audit entry and event
+	affected, err := input.ExecForKeys(ctx, tx, "DELETE FROM "+tableName, nil, "{$pkName}", ids, loader.DefaultMaxBatch)
+	if err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_delete_item", tableName)
+	}
+GO;
+        $trashMutations = "";
+        if($this->isTrashEnabled($tableName))
+        {
+            $trashMove = <<<GO
+	if before != nil {
+		// Keep a copy of the record in the trash, so it can be restored
+		if err := trash.Move(ctx, tx, tableName, args.ID, before.auditSnapshot()); err != nil {
+			return false, apperror.Internal(ctx, err, "failed_to_delete_item", tableName)
+		}
+	}
+
+GO;
+            $deleteAll = <<<GO
+	// Delete exactly the records copied to the trash, so none is deleted without a copy
+	affected, err := trash.MoveAll(ctx, tx, tableName, "{$pkName}", ids, func(id $pkType) map[string]interface{} { return before[id].auditSnapshot() }, loader.DefaultMaxBatch)
+	if err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_delete_item", tableName)
+	}
+GO;
+            $trashMutations = <<<GO
+
+// Restore{$pascalName} puts the most recently deleted version of a {$tableName} back from the trash.
+func (r *{$pascalName}QueryResolver) Restore{$pascalName}(ctx context.Context, args struct{ ID $pkType }) (*{$pascalName}Resolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDelete); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := trash.Restore(ctx, tx, tableName, args.ID, {$columnMapName}); err != nil {
+		return nil, err
+	}
+
+	txCtx := database.WithTx(ctx, tx.Tx)
+	item, err := r.find{$pascalName}(txCtx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, tableName, args.ID, audit.ActionRestore, nil, item.auditSnapshot()); err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	publish{$pascalName}Change(ctx, event.ActionCreated, item)
+	return item, nil
+}
+
+// Purge{$pascalName} permanently removes the deleted versions of a {$tableName} from the trash.
+func (r *{$pascalName}QueryResolver) Purge{$pascalName}(ctx context.Context, args struct{ ID $pkType }) (bool, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDelete); err != nil {
+		return false, err
+	}
+
+	tableName := "{$tableName}"
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	removed, err := trash.Purge(ctx, tx, tableName, args.ID)
+	if err != nil {
+		return false, err
+	}
+	if removed == 0 {
+		return false, apperror.NotFound(util.T(ctx, "no_item_found_in_trash", tableName, args.ID))
+	}
+
+	if err := audit.Record(ctx, tx, tableName, args.ID, audit.ActionPurge, nil, nil); err != nil {
+		return false, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+GO;
+        }
+
         $defs = [];
+        $rules = [];
+        $values = [];
         foreach($colInfo as $info)
         {
-            if(in_array($info['columnName'], $backendHandledColumnNames))
+            if(in_array($info['columnName'], $backendHandledColumnNames) || $info['columnName'] === $versionColumn)
             {
                 continue;
             }
-            $defs[] = sprintf("\t%-{$maxLength}s *%s", $info['name'], $info['type']);
+            $defs[] = sprintf("\t%-{$maxLength}s *%s", $info['name'], $info['apiType']);
+            $rule = $this->getValidationRule($info['columnName'], $tableInfo['columns'][$info['columnName']]);
+            if($rule !== null)
+            {
+                $rules[] = "\t$rule,";
+                $values[] = sprintf("\t\t%-" . ($maxLength + 3) . "s in.%s,", "\"{$info['columnName']}\":", $info['name']);
+            }
         }
         $typeDefinitions = implode("\r\n", $defs);
+        $rulesName = $this->camelCase($tableName) . "Rules";
+        $validationRules = implode("\r\n", $rules);
+        $validationValues = implode("\r\n", $values);
 
         $activeField = $this->activeField;
 
@@ -893,97 +1645,449 @@ type {$pascalName}Input struct {
 $typeDefinitions
 }
 
-// Create{$pascalName} creates a new {$tableName}.
-func (r *{$pascalName}QueryResolver) Create{$pascalName}(ctx context.Context, args struct{ Input {$pascalName}Input }) (*{$pascalName}Resolver, error) {
+// {$rulesName} are the validation rules of the fields of {$pascalName}Input.
+var {$rulesName} = validation.Rules{
+$validationRules
+}
+
+// validate{$pascalName}Input checks a {$pascalName}Input against {$rulesName}.
+// When partial is true, as for updates, the required fields may be left unset.
+func validate{$pascalName}Input(ctx context.Context, in {$pascalName}Input, partial bool) error {
+	return validation.Validate(ctx, {$rulesName}, map[string]interface{}{
+$validationValues
+	}, partial)
+}
+
+// Create{$pascalName} creates a new {$tableName}.
+func (r *{$pascalName}QueryResolver) Create{$pascalName}(ctx context.Context, args struct{ Input {$pascalName}Input }) (*{$pascalName}Resolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionCreate); err != nil {
+		return nil, err
+	}
+	if err := validate{$pascalName}Input(ctx, args.Input, false); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Read through the transaction and write the audit trail in it, so it is committed with the change
+	txCtx := database.WithTx(ctx, tx.Tx)
+	id, err := r.insert{$pascalName}(ctx, tx, args.Input)
+	if err != nil {
+		return nil, err
+	}
+	item, err := r.find{$pascalName}(txCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, tableName, id, audit.ActionCreate, nil, item.auditSnapshot()); err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	publish{$pascalName}Change(ctx, event.ActionCreated, item)
+	return item, nil
+}
+
+// insert{$pascalName} inserts a {$tableName} with db, which may be a transaction, and returns its primary key.
+func (r *{$pascalName}QueryResolver) insert{$pascalName}(ctx context.Context, db database.Executor, in {$pascalName}Input) ($pkType, error) {
+	tableName := "{$tableName}"
+$uuid
+    var fields []string
+    var placeholders []string
+    var params []interface{}
+
+$paramSet
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+$insertCode
+}
+
+// update{$pascalName}Fields returns the assignments and parameters that apply an input to a {$tableName}.
+// Only the fields set in the input are changed.
+func update{$pascalName}Fields(ctx context.Context, in {$pascalName}Input) ([]string, []interface{}) {
+	var fields []string
+	var params []interface{}
+
+$updateCodes
+	return fields, params
+}
+
+// Update{$pascalName} updates an existing {$tableName}.
+func (r *{$pascalName}QueryResolver) Update{$pascalName}(ctx context.Context, args struct {
+	ID    string
+	Input {$pascalName}Input{$expectedVersionArg}
+}) (*{$pascalName}Resolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if err := validate{$pascalName}Input(ctx, args.Input, true); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+	primaryKey := "{$pkName}"
+
+	fields, params := update{$pascalName}Fields(ctx, args.Input)
+	params = append(params, args.ID)
+
+	if len(fields) == 0 {
+		return nil, apperror.Validation(util.T(ctx, "no_fields_to_update"))
+	}
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Read through the transaction and write the audit trail in it, so it is committed with the change
+	txCtx := database.WithTx(ctx, tx.Tx)
+	before, err := r.find{$pascalName}(txCtx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, apperror.NotFound(util.T(ctx, "no_item_found_with_id", tableName, args.ID))
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", tableName, strings.Join(fields, ", "), primaryKey)
+$versionCheck	{$updateResult} tx.ExecContext(ctx, query, params...)
+	if err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_update_item", tableName)
+	}
+$versionVerify$returnUpdateCodes
+	item, err := r.find{$pascalName}(txCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, tableName, id, audit.ActionUpdate, before.auditSnapshot(), item.auditSnapshot()); err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	publish{$pascalName}Change(ctx, event.ActionUpdated, item)
+	return item, nil
+}
+
+// Delete{$pascalName} deletes a {$tableName} by its ID.
+func (r *{$pascalName}QueryResolver) Delete{$pascalName}(ctx context.Context, args struct{ ID $pkType }) (bool, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDelete); err != nil {
+		return false, err
+	}
+
+	tableName := "{$tableName}"
+	primaryKey := "{$pkName}"
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Read through the transaction and write the audit trail in it, so it is committed with the change
+	txCtx := database.WithTx(ctx, tx.Tx)
+	before, err := r.find{$pascalName}(txCtx, args.ID)
+	if err != nil {
+		return false, err
+	}
+$trashMove
+	sqlQuery := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", tableName, primaryKey)
+
+	result, err := tx.ExecContext(ctx, sqlQuery, args.ID)
+	if err != nil {
+		return false, apperror.Internal(ctx, err, "failed_to_delete_item", tableName)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal(ctx, err, "failed_to_check_rows_affected")
+	}
+	if rowsAffected == 0 {
+		return false, apperror.NotFound(util.T(ctx, "no_item_found_with_id", tableName, args.ID))
+	}
+
+	if err := audit.Record(ctx, tx, tableName, args.ID, audit.ActionDelete, before.auditSnapshot(), nil); err != nil {
+		return false, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	publish{$pascalName}Change(ctx, event.ActionDeleted, before)
+	return true, nil
+}
+$trashMutations
+// Toggle{$pascalName}Active changes the active status of a {$tableName}.
+func (r *{$pascalName}QueryResolver) Toggle{$pascalName}Active(ctx context.Context, args struct {
+	ID $pkType
+	{$activeFieldPascal} bool
+}) (*{$pascalName}Resolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionToggle); err != nil {
+		return nil, err
+	}
+
+	tableName := "{$tableName}"
+	primaryKey := "{$pkName}"
+	activeField := "{$activeField}"
+
+    var fields []string
+	var params []interface{}
+
+$toggleCodes
+    params = append(params, args.ID)
+
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Read through the transaction and write the audit trail in it, so it is committed with the change
+	txCtx := database.WithTx(ctx, tx.Tx)
+	before, err := r.find{$pascalName}(txCtx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, apperror.NotFound(util.T(ctx, "no_item_found_with_id", tableName, args.ID))
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", tableName, strings.Join(fields, ", "), primaryKey)
+	_, err = tx.ExecContext(ctx, query, params...)
+	if err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_change_status", tableName, activeField)
+	}
+	item, err := r.find{$pascalName}(txCtx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := audit.Record(ctx, tx, tableName, args.ID, audit.ActionToggle, before.auditSnapshot(), item.auditSnapshot()); err != nil {
+		return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	publish{$pascalName}Change(ctx, event.ActionUpdated, item)
+	return item, nil
+}
+
+// Create{$pascalNamePlural} creates several {$tableName} records in one transaction.
+// If one of them fails, none is created.
+func (r *{$pascalName}QueryResolver) Create{$pascalNamePlural}(ctx context.Context, args struct{ Inputs []{$pascalName}Input }) (*BulkResultResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionCreate); err != nil {
+		return nil, err
+	}
+	for i, in := range args.Inputs {
+		if err := validate{$pascalName}Input(ctx, in, false); err != nil {
+			return nil, validation.At(err, "inputs", i)
+		}
+	}
 
-	tableName := "{$tableName}"
-$uuid
-    var fields []string
-    var placeholders []string
-    var params []interface{}
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-$paramSet
+	ids := make([]$pkType, 0, len(args.Inputs))
+	for _, in := range args.Inputs {
+		id, err := r.insert{$pascalName}(ctx, tx, in)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
 
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
-	_, err := r.root.DBConnection().ExecContext(ctx, query, params...)
+	// Read through the transaction and write the audit trail in it, so it is committed with the changes
+	txCtx := database.WithTx(ctx, tx.Tx)
+	after, err := r.read{$pascalNamePlural}(txCtx, ids)
 	if err != nil {
-		return nil, errors.New(util.T(ctx, "failed_to_create_item", tableName, err))
+		return nil, err
 	}
-$getLastId	return r.{$pascalName}(ctx, struct{ ID $pkType }{ID: id})
+	for _, id := range ids {
+		if err := audit.Record(ctx, tx, "{$tableName}", id, audit.ActionCreate, nil, after[id].auditSnapshot()); err != nil {
+			return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", "{$tableName}")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		publish{$pascalName}Change(ctx, event.ActionCreated, after[id])
+	}
+	return newBulkResultResolver(ids, int64(len(ids))), nil
 }
 
-// Update{$pascalName} updates an existing {$tableName}.
-func (r *{$pascalName}QueryResolver) Update{$pascalName}(ctx context.Context, args struct {
-	ID    string
-	Input {$pascalName}Input
-}) (*{$pascalName}Resolver, error) {
+// Update{$pascalNamePlural} applies an input to the {$tableName} records matching the filter in one transaction.
+// An empty filter is refused unless force is true.
+func (r *{$pascalName}QueryResolver) Update{$pascalNamePlural}(ctx context.Context, args struct {
+	Filter *[]*input.FilterInput
+	Input  {$pascalName}Input
+	Force  *bool
+}) (*BulkResultResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionUpdate); err != nil {
+		return nil, err
+	}
 
 	tableName := "{$tableName}"
-	primaryKey := "{$pkName}"
 
-	var fields []string
-	var params []interface{}
+	whereSQL, whereParams, err := input.BuildBulkFilter(ctx, args.Filter, args.Force, {$columnMapName}, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
 
-$updateCodes
-    params = append(params, args.ID)
+	if err := validate{$pascalName}Input(ctx, args.Input, true); err != nil {
+		return nil, err
+	}
 
+	fields, params := update{$pascalName}Fields(ctx, args.Input)
 	if len(fields) == 0 {
-		return nil, errors.New(util.T(ctx, "no_fields_to_update"))
+		return nil, apperror.Validation(util.T(ctx, "no_fields_to_update"))
 	}
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", tableName, strings.Join(fields, ", "), primaryKey)
-	_, err := r.root.DBConnection().ExecContext(ctx, query, params...)
+	tx, err := r.root.BeginTx(ctx)
 	if err != nil {
-		return nil, errors.New(util.T(ctx, "failed_to_update_item", tableName, err))
+		return nil, err
 	}
-$returnUpdateCodes
-}
-
-// Delete{$pascalName} deletes a {$tableName} by its ID.
-func (r *{$pascalName}QueryResolver) Delete{$pascalName}(ctx context.Context, args struct{ ID $pkType }) (bool, error) {
+	defer tx.Rollback()
 
-	tableName := "{$tableName}"
-	primaryKey := "{$pkName}"
+	ids, err := r.select{$pascalName}IDs(ctx, tx, whereSQL, whereParams)
+	if err != nil {
+		return nil, err
+	}
 
-	sqlQuery := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", tableName, primaryKey)
+	// Read through the transaction and write the audit trail in it, so it is committed with the changes
+	txCtx := database.WithTx(ctx, tx.Tx)
+	before, err := r.read{$pascalNamePlural}(txCtx, ids)
+	if err != nil {
+		return nil, err
+	}
 
-	result, err := r.root.DBConnection().ExecContext(ctx, sqlQuery, args.ID)
+	// Update the selected records rather than those matching the filter now, so every change has its audit entry and event
+	query := fmt.Sprintf("UPDATE %s SET %s", tableName, strings.Join(fields, ", "))
+	affected, err := input.ExecForKeys(ctx, tx, query, params, "{$pkName}", ids, loader.DefaultMaxBatch)
 	if err != nil {
-		return false, errors.New(util.T(ctx, "failed_to_delete_item", tableName, err))
+		return nil, apperror.Internal(ctx, err, "failed_to_update_item", tableName)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	after, err := r.read{$pascalNamePlural}(txCtx, ids)
 	if err != nil {
-		return false, errors.New(util.T(ctx, "failed_to_check_rows_affected", err))
+		return nil, err
 	}
-	if rowsAffected == 0 {
-		return false, errors.New(util.T(ctx, "no_item_found_with_id", tableName, args.ID))
+	for _, id := range ids {
+		if err := audit.Record(ctx, tx, tableName, id, audit.ActionUpdate, before[id].auditSnapshot(), after[id].auditSnapshot()); err != nil {
+			return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+		}
 	}
 
-	return true, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		publish{$pascalName}Change(ctx, event.ActionUpdated, after[id])
+	}
+	return newBulkResultResolver(ids, affected), nil
 }
 
-// Toggle{$pascalName}Active changes the active status of a {$tableName}.
-func (r *{$pascalName}QueryResolver) Toggle{$pascalName}Active(ctx context.Context, args struct {
-	ID $pkType
-	{$activeFieldPascal} bool
-}) (*{$pascalName}Resolver, error) {
+// Delete{$pascalNamePlural} deletes the {$tableName} records matching the filter in one transaction.
+// An empty filter is refused unless force is true.
+func (r *{$pascalName}QueryResolver) Delete{$pascalNamePlural}(ctx context.Context, args struct {
+	Filter *[]*input.FilterInput
+	Force  *bool
+}) (*BulkResultResolver, error) {
+	if err := auth.Authorize(ctx, r.root.DBConnection(ctx), "{$tableName}", auth.ActionDelete); err != nil {
+		return nil, err
+	}
+
 	tableName := "{$tableName}"
-	primaryKey := "{$pkName}"
-	activeField := "{$activeField}"
 
-    var fields []string
-	var params []interface{}
+	whereSQL, whereParams, err := input.BuildBulkFilter(ctx, args.Filter, args.Force, {$columnMapName}, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
 
-$toggleCodes
-    params = append(params, args.ID)
+	tx, err := r.root.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", tableName, strings.Join(fields, ", "), primaryKey)
-	_, err := r.root.DBConnection().ExecContext(ctx, query, params...)
+	ids, err := r.select{$pascalName}IDs(ctx, tx, whereSQL, whereParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read through the transaction and write the audit trail in it, so it is committed with the changes
+	txCtx := database.WithTx(ctx, tx.Tx)
+	before, err := r.read{$pascalNamePlural}(txCtx, ids)
+	if err != nil {
+		return nil, err
+	}
+$deleteAll
+
+	for _, id := range ids {
+		if err := audit.Record(ctx, tx, tableName, id, audit.ActionDelete, before[id].auditSnapshot(), nil); err != nil {
+			return nil, apperror.Internal(ctx, err, "failed_to_write_audit_trail", tableName)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		publish{$pascalName}Change(ctx, event.ActionDeleted, before[id])
+	}
+	return newBulkResultResolver(ids, affected), nil
+}
+
+// select{$pascalName}IDs returns the primary keys of the {$tableName} records matching a WHERE clause.
+func (r *{$pascalName}QueryResolver) select{$pascalName}IDs(ctx context.Context, db database.Executor, whereSQL string, params []interface{}) ([]$pkType, error) {
+	sqlQuery := fmt.Sprintf("SELECT %s FROM %s %s", "{$pkName}", "{$tableName}", whereSQL)
+	rows, err := db.QueryContext(ctx, sqlQuery, params...)
 	if err != nil {
-		return nil, errors.New(util.T(ctx, "failed_to_change_status", tableName, activeField, err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []$pkType{}
+	for rows.Next() {
+		var id $pkType
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// read{$pascalNamePlural} reads the {$tableName} records with the given IDs for the audit trail and the change events.
+// The IDs are read in batches, so the number of placeholders stays within the limits of the databases.
+func (r *{$pascalName}QueryResolver) read{$pascalNamePlural}(ctx context.Context, ids []$pkType) (map[$pkType]*{$pascalName}Resolver, error) {
+	records := make(map[$pkType]*{$pascalName}Resolver, len(ids))
+	for start := 0; start < len(ids); start += loader.DefaultMaxBatch {
+		end := min(start+loader.DefaultMaxBatch, len(ids))
+		items, err := r.find{$pascalNamePlural}ByID(ctx, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for id, item := range items {
+			records[id] = item
+		}
 	}
-	return r.{$pascalName}(ctx, struct{ ID $pkType }{ID: args.ID})
+	return records, nil
 }
 GO;
     }
@@ -1009,6 +2113,10 @@ require (
 	github.com/gorilla/sessions v1.4.0
 	github.com/graph-gophers/graphql-go v1.8.0
 	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
+	github.com/microsoft/go-mssqldb v1.7.2
+	golang.org/x/crypto v0.42.0
+	golang.org/x/net v0.43.0
 	modernc.org/sqlite v1.40.1
 )
 
@@ -1054,6 +2162,151 @@ MOD;
         return $name;
     }
 
+    /**
+     * Finds the tables that refer to a table through a foreign key.
+     *
+     * Each relation is exposed as a paginated child collection on the referenced type,
+     * e.g. `category.products` for the `category_id` column of `product`.
+     *
+     * @param string $tableName The name of the referenced table.
+     * @return array List of relations, each containing the child `table` and its foreign key `column`.
+     */
+    private function getReverseRelations($tableName)
+    {
+        $relations = [];
+        foreach ($this->analyzedSchema as $childName => $childInfo) {
+            foreach ($childInfo['columns'] as $columnName => $col) {
+                if ($col['isForeignKey'] && $col['references'] === $tableName) {
+                    $relations[] = ['table' => $childName, 'column' => $columnName];
+                }
+            }
+        }
+        return $relations;
+    }
+
+    /**
+     * Builds the validation rule of an input field from the metadata of its column.
+     *
+     * The rule is derived from the column definition:
+     * - `required` for NOT NULL columns without a default value that are not generated by the database
+     * - `maxLength` from the length of CHAR and VARCHAR columns
+     * - `min` and `max` from the range of TINYINT, SMALLINT and UNSIGNED columns
+     * - the `email` and `url` formats for columns named `email`, `*_email`, `url`, `*_url` or `website`
+     *
+     * The `validation` object of a column in the schema overrides these, and may also set
+     * `minLength`, `pattern` and `format`.
+     *
+     * @param string $columnName The name of the column.
+     * @param array $col The column information.
+     * @return string|null The Go literal of the rule, or null if the field has no constraint.
+     */
+    private function getValidationRule($columnName, $col)
+    {
+        $type = strtolower($col['type']);
+        $rule = [];
+
+        $generated = $col['isPrimaryKey'] && ($col['isAutoIncrement'] || in_array($col['primaryKeyValue'], ['autoincrement', 'autogenerated']));
+        $hasDefault = isset($col['default']) && $col['default'] !== '' && strtolower((string) $col['default']) !== 'null';
+        if (!$col['nullable'] && !$hasDefault && !$generated) {
+            $rule['required'] = true;
+        }
+        if (strpos($type, 'char') !== false && is_numeric($col['length']) && (int) $col['length'] > 0) {
+            $rule['maxLength'] = (int) $col['length'];
+        }
+        $unsigned = strpos($type, 'unsigned') !== false;
+        $isBool = $this->mapDbTypeToGoTypeAsModel($col['type'], $col['length']) === 'bool';
+        if (strpos($type, 'tinyint') !== false && !$isBool) {
+            $rule['min'] = $unsigned ? 0 : -128;
+            $rule['max'] = $unsigned ? 255 : 127;
+        } else if (strpos($type, 'smallint') !== false) {
+            $rule['min'] = $unsigned ? 0 : -32768;
+            $rule['max'] = $unsigned ? 65535 : 32767;
+        } else if ($unsigned) {
+            $rule['min'] = 0;
+        }
+        if ($columnName === 'email' || PicoStringUtil::endsWith($columnName, '_email')) {
+            $rule['format'] = 'email';
+        } else if ($columnName === 'url' || $columnName === 'website' || PicoStringUtil::endsWith($columnName, '_url')) {
+            $rule['format'] = 'url';
+        }
+        foreach (['required', 'minLength', 'maxLength', 'min', 'max', 'format', 'pattern'] as $key) {
+            if (array_key_exists($key, $col['validation'])) {
+                $rule[$key] = $col['validation'][$key];
+            }
+        }
+
+        $parts = [];
+        if (!empty($rule['required'])) {
+            $parts[] = "Required: true";
+        }
+        foreach (['minLength' => 'MinLength', 'maxLength' => 'MaxLength'] as $key => $goField) {
+            if (isset($rule[$key]) && is_numeric($rule[$key]) && (int) $rule[$key] > 0) {
+                $parts[] = "$goField: " . (int) $rule[$key];
+            }
+        }
+        foreach (['min' => 'Min', 'max' => 'Max'] as $key => $goField) {
+            if (isset($rule[$key]) && is_numeric($rule[$key])) {
+                $parts[] = "$goField: validation.Float(" . (0 + $rule[$key]) . ")";
+            }
+        }
+        if (isset($rule['format']) && in_array($rule['format'], ['email', 'url'])) {
+            $parts[] = "Format: validation.Format" . ($rule['format'] === 'email' ? 'Email' : 'URL');
+        }
+        if (!empty($rule['pattern'])) {
+            $parts[] = "Pattern: " . json_encode((string) $rule['pattern'], JSON_UNESCAPED_SLASHES | JSON_UNESCAPED_UNICODE);
+        }
+        if (empty($parts)) {
+            return null;
+        }
+        return "{Field: \"{$columnName}\", " . implode(", ", $parts) . "}";
+    }
+
+    /**
+     * Finds the column that holds the version of the records of a table, for optimistic concurrency.
+     *
+     * A `version` column is incremented by every update and is preferred. Otherwise the column
+     * that holds the time of the last change (`timeEdit`) is used.
+     *
+     * @param array $tableInfo The table information.
+     * @return string|null The name of the version column, or null if the table has none.
+     */
+    private function getVersionColumn($tableInfo)
+    {
+        if (isset($tableInfo['columns']['version']) && !$tableInfo['columns']['version']['isPrimaryKey']) {
+            return 'version';
+        }
+        if (isset($this->backendHandledColumns['timeEdit']) && isset($tableInfo['columns'][$this->backendHandledColumns['timeEdit']['columnName']])) {
+            return $this->backendHandledColumns['timeEdit']['columnName'];
+        }
+        return null;
+    }
+
+    /**
+     * Checks whether the deleted records of a table are moved to the trash.
+     *
+     * A table uses the trash when the schema also defines a `{table}_trash` entity, as the modules
+     * of MagicAppBuilder with a trash do, or when its entity sets `trash` to true.
+     * Trash tables themselves never use the trash.
+     *
+     * @param string $tableName The name of the table.
+     * @return bool True if deleted records are moved to the trash.
+     */
+    private function isTrashEnabled($tableName)
+    {
+        if (PicoStringUtil::endsWith($tableName, '_trash')) {
+            return false;
+        }
+        foreach ($this->schema['entities'] as $entity) {
+            if ($entity['name'] === $tableName . '_trash') {
+                return true;
+            }
+            if ($entity['name'] === $tableName && !empty($entity['trash'])) {
+                return true;
+            }
+        }
+        return false;
+    }
+
     /**
      * Generates a model file (struct) for a given table.
      *
@@ -1137,16 +2390,22 @@ GO;
         $allTypes = "";
         $allQueries = "";
         $allMutations = "";
+        $allSubscriptions = "";
 
         foreach ($this->analyzedSchema as $tableName => $tableInfo) {
             $schemaParts = $this->getSchemaPartsForTable($tableName, $tableInfo);
             $allTypes .= $schemaParts['types'] . "\n";
             $allQueries .= $schemaParts['queries'] . "\n";
             $allMutations .= $schemaParts['mutations'] . "\n";
+            $allSubscriptions .= $schemaParts['subscriptions'];
         }
 
         return <<<GQL
 scalar Any
+scalar Date
+scalar DateTime
+scalar Decimal
+scalar JSON
 
 enum SortDirection {
     ASC
@@ -1163,6 +2422,12 @@ enum FilterOperator {
     LESS_THAN_OR_EQUALS
     IN
     NOT_IN
+    IS_NULL
+    IS_NOT_NULL
+    BETWEEN
+    STARTS_WITH
+    ENDS_WITH
+    NOT_CONTAINS
 }
 
 input SortInput {
@@ -1171,20 +2436,117 @@ input SortInput {
 }
 
 input FilterInput {
-    field: String!
+    field: String
     value: Any
     operator: FilterOperator
+    ignoreCase: Boolean
+    and: [FilterInput]
+    or: [FilterInput]
+    not: FilterInput
+}
+
+type PageInfo {
+    hasNextPage: Boolean!
+    hasPreviousPage: Boolean!
+    startCursor: String
+    endCursor: String
+}
+
+enum AggregateFunction {
+    COUNT
+    SUM
+    AVG
+    MIN
+    MAX
+}
+
+input MetricInput {
+    fn: AggregateFunction!
+    field: String
+}
+
+type AggregateGroup {
+    field: String!
+    value: Any
+}
+
+type AggregateMetric {
+    fn: AggregateFunction!
+    field: String
+    value: Any
+}
+
+type AggregateBucket {
+    group: [AggregateGroup!]!
+    metrics: [AggregateMetric!]!
+}
+
+type BulkMutationResult {
+    affected: Int!
+    ids: [String!]!
+}
+
+type AuditLog {
+    audit_log_id: String!
+    entity: String!
+    record_id: String!
+    action: String!
+    before_data: String
+    after_data: String
+    admin_id: String
+    ip_address: String
+    time_create: String
+}
+
+type AuditLogPage {
+    items: [AuditLog]
+    total: Int
+    page: Int
+    limit: Int
+    totalPages: Int
+    hasNext: Boolean
+    hasPrevious: Boolean
+}
+
+type TrashItem {
+    trash_id: String!
+    entity: String!
+    record_id: String!
+    data: String
+    admin_delete: String
+    ip_delete: String
+    time_delete: String
+}
+
+type TrashPage {
+    items: [TrashItem]
+    total: Int
+    page: Int
+    limit: Int
+    totalPages: Int
+    hasNext: Boolean
+    hasPrevious: Boolean
+}
+
+enum ChangeAction {
+    CREATED
+    UPDATED
+    DELETED
 }
 
 $allTypes
 
 type Query {
 $allQueries
+    auditLogs(limit: Int, offset: Int, page: Int, orderBy: [SortInput], filter: [FilterInput]): AuditLogPage
 }
 
 type Mutation {
 $allMutations
 }
+
+type Subscription {
+$allSubscriptions}
 GQL;
     }
 
@@ -1193,7 +2555,7 @@ GQL;
      *
      * @param string $tableName The name of the table.
      * @param array $tableInfo The table information.
-     * @return array An array containing 'types', 'queries', and 'mutations' strings.
+     * @return array An array containing 'types', 'queries', 'mutations', and 'subscriptions' strings.
      */
     private function getSchemaPartsForTable($tableName, $tableInfo)
     {
@@ -1204,7 +2566,7 @@ GQL;
         // Type fields
         $typeFields = "";
         foreach ($tableInfo['columns'] as $colName => $colInfo) {
-            $gqlType = $this->mapGoTypeToGqlType($this->mapDbTypeToGoType($colInfo['type'], $colInfo['length']));
+            $gqlType = $this->mapGoTypeToGqlType($this->mapColumnToApiType($colInfo));
             $fieldName = $colName;
             $typeFields .= "    $fieldName: $gqlType\n";
 
@@ -1214,6 +2576,13 @@ GQL;
                 $typeFields .= "    $refFieldName: $refPascalName\n";
             }
         }
+        foreach ($this->getReverseRelations($tableName) as $relation) {
+            $childPascalName = $this->pascalCase($relation['table']);
+            $childFieldName = $this->pluralize($this->camelCase($relation['table']));
+            $typeFields .= "    $childFieldName(limit: Int, offset: Int, page: Int, orderBy: [SortInput], filter: [FilterInput]): {$childPascalName}Page\n";
+        }
+        $typeFields .= "    rowVersion: String\n";
+        $versionColumn = $this->getVersionColumn($tableInfo);
 
         // Input fields
         $inputFields = "";
@@ -1222,10 +2591,10 @@ GQL;
             if ($colInfo['isPrimaryKey'] && ($colInfo['isAutoIncrement'] || $colInfo['primaryKeyValue'] == 'autogenerated')) {
                 continue;
             }
-            if (in_array($colName, $backendHandledColumnNames)) {
+            if (in_array($colName, $backendHandledColumnNames) || $colName === $versionColumn) {
                 continue;
             }
-            $gqlType = $this->mapGoTypeToGqlType($this->mapDbTypeToGoType($colInfo['type'], $colInfo['length']));
+            $gqlType = $this->mapGoTypeToGqlType($this->mapColumnToApiType($colInfo));
             $fieldName = $colName;
             $inputFields .= "    $fieldName: $gqlType\n";
         }
@@ -1233,7 +2602,7 @@ GQL;
         $pkGqlType = 'String!';
         foreach ($tableInfo['columns'] as $col) {
             if ($col['isPrimaryKey']) {
-                $pkGqlType = $this->mapGoTypeToGqlType($this->mapDbTypeToGoType($col['type'], $col['length'])) . '!';
+                $pkGqlType = $this->mapGoTypeToGqlType($this->mapColumnToApiType($col)) . '!';
                 break;
             }
         }
@@ -1254,26 +2623,64 @@ type {$pascalName}Page {
     hasNext: Boolean
     hasPrevious: Boolean
 }
+
+type {$pascalName}Edge {
+    cursor: String!
+    node: $pascalName
+}
+
+type {$pascalName}Connection {
+    edges: [{$pascalName}Edge]
+    pageInfo: PageInfo!
+    totalCount: Int
+}
+
+type {$pascalName}Change {
+    action: ChangeAction!
+    id: String!
+    $camelName: $pascalName
+}
 GQL;
         $camelMethodName = $camelName;
         $pluralMethodName = $pluralCamelName;
 
         $queries = "    $camelMethodName(id: $pkGqlType): $pascalName\n";
         $queries .= "    $pluralMethodName(limit: Int, offset: Int, page: Int, orderBy: [SortInput], filter: [FilterInput]): {$pascalName}Page\n";
+        $queries .= "    {$pluralMethodName}Connection(first: Int, after: String, last: Int, before: String, orderBy: [SortInput], filter: [FilterInput]): {$pascalName}Connection\n";
+        $queries .= "    {$camelMethodName}Aggregate(filter: [FilterInput], groupBy: [String], metrics: [MetricInput]): [AggregateBucket!]!\n";
+        $queries .= "    {$camelMethodName}History(id: $pkGqlType, limit: Int, offset: Int, page: Int, orderBy: [SortInput], filter: [FilterInput]): AuditLogPage\n";
+        if ($this->isTrashEnabled($tableName)) {
+            $queries .= "    trash" . $this->pluralize($pascalName) . "(limit: Int, offset: Int, page: Int, orderBy: [SortInput], filter: [FilterInput]): TrashPage\n";
+        }
 
         $mutations = "    create{$pascalName}(input: {$pascalName}Input!): $pascalName\n";
-        $mutations .= "    update{$pascalName}(id: $pkGqlType, input: {$pascalName}Input!): $pascalName\n";
+        if ($versionColumn !== null) {
+            $mutations .= "    update{$pascalName}(id: $pkGqlType, input: {$pascalName}Input!, expectedVersion: String): $pascalName\n";
+        } else {
+            $mutations .= "    update{$pascalName}(id: $pkGqlType, input: {$pascalName}Input!): $pascalName\n";
+        }
         $mutations .= "    delete{$pascalName}(id: $pkGqlType): Boolean!\n";
+        $pluralPascalName = $this->pluralize($pascalName);
+        $mutations .= "    create{$pluralPascalName}(inputs: [{$pascalName}Input!]!): BulkMutationResult!\n";
+        $mutations .= "    update{$pluralPascalName}(filter: [FilterInput], input: {$pascalName}Input!, force: Boolean): BulkMutationResult!\n";
+        $mutations .= "    delete{$pluralPascalName}(filter: [FilterInput], force: Boolean): BulkMutationResult!\n";
+        if ($this->isTrashEnabled($tableName)) {
+            $mutations .= "    restore{$pascalName}(id: $pkGqlType): $pascalName\n";
+            $mutations .= "    purge{$pascalName}(id: $pkGqlType): Boolean!\n";
+        }
 
         if ($tableInfo['hasActiveColumn']) {
             $activeField = $this->camelCase($this->activeField);
             $mutations .= "    toggle{$pascalName}Active(id: {$pkGqlType}, $activeField: Boolean!): $pascalName\n";
         }
 
+        $subscriptions = "    {$camelName}Changed(filter: [FilterInput]): {$pascalName}Change!\n";
+
         return [
             'types' => $types,
             'queries' => $queries,
-            'mutations' => $mutations
+            'mutations' => $mutations,
+            'subscriptions' => $subscriptions
         ];
     }
 
@@ -1294,6 +2701,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"{$moduleName}/constant"
+	"{$moduleName}/util"
 	"log"
 	"net/http"
 	"strings"
@@ -1306,17 +2714,6 @@ type AuthHandler struct {
 	Store sessions.Store
 }
 
-// doubleSha1 calculates sha1(sha1(password)).
-func doubleSha1(input string) string {
-	h1 := sha1.New()
-	h1.Write([]byte(input))
-	step1 := hex.EncodeToString(h1.Sum(nil))
-
-	h2 := sha1.New()
-	h2.Write([]byte(step1))
-	return hex.EncodeToString(h2.Sum(nil))
-}
-
 // singleSha1 calculates sha1(password) to be stored in the session.
 func singleSha1(input string) string {
 	h := sha1.New()
@@ -1378,12 +2775,18 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Compare sha1(sha1(password))
-	if doubleSha1(password) != dbPassword {
+	// Verify the password against the stored hash (argon2id, bcrypt or legacy sha1(sha1(password)))
+	ok, needsRehash := util.VerifyPassword(password, dbPassword)
+	if !ok {
 		h.respondAuthError(w, "Invalid credentials")
 		return
 	}
 
+	// Silently upgrade legacy or outdated hashes
+	if needsRehash {
+		h.rehashPassword(dbAdminId, password)
+	}
+
 	// Success -> save session
 	session, _ := h.Store.Get(r, constant.SessionKey)
 	session.Values[constant.SessionUsername] = dbUsername
@@ -1411,6 +2814,20 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
+// rehashPassword replaces the stored password hash with one produced by the configured hasher.
+// Failures are only logged because the login itself has already succeeded.
+func (h *AuthHandler) rehashPassword(adminId, password string) {
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for admin %s: %v", adminId, err)
+		return
+	}
+	_, err = h.DB.Exec("UPDATE admin SET password = ? WHERE admin_id = ?", hashedPassword, adminId)
+	if err != nil {
+		log.Printf("Failed to store rehashed password for admin %s: %v", adminId, err)
+	}
+}
+
 func (h *AuthHandler) respondAuthError(w http.ResponseWriter, msg string) {
 	w.Header().Set("HTTP/1.1", "401 Unauthorized")
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -1465,6 +2882,14 @@ GO;
                 return 'Float';
             case 'bool':
                 return 'Boolean';
+            case 'input.Date':
+                return 'Date';
+            case 'input.DateTime':
+                return 'DateTime';
+            case 'input.Decimal':
+                return 'Decimal';
+            case 'input.JSON':
+                return 'JSON';
             case 'time.Time':
                 return 'String';
             case 'string':
@@ -1497,8 +2922,44 @@ GO;
         $manualContent .= "    DB_DRIVER=mysql\n";
         $manualContent .= "    SERVER_PORT=8080\n";
         $manualContent .= "    SESSION_SECRET=change-this-to-a-random-string\n";
+        $manualContent .= "    DB_SSL_MODE=disable\n";
+        $manualContent .= "    DB_SCHEMA=\n";
         $manualContent .= "    REQUIRE_LOGIN=" . ($this->requireLogin ? 'true' : 'false') . "\n";
+        $manualContent .= "    PASSWORD_HASH_ALGORITHM=argon2id\n";
+        $manualContent .= "    REQUIRE_PERMISSION=false\n";
+        $manualContent .= "    SUPERUSER_LEVEL_ID=superuser\n";
+        $manualContent .= "    TRASH_REQUIRED=false\n";
+        $manualContent .= "    GRAPHQL_MAX_DEPTH=10\n";
+        $manualContent .= "    GRAPHQL_MAX_COST=10000\n";
+        $manualContent .= "    GRAPHQL_MAX_PAGE_SIZE=100\n";
+        $manualContent .= "    GRAPHQL_PERSISTED_QUERY_STORE=memory\n";
+        $manualContent .= "    GRAPHQL_PERSISTED_QUERY_CACHE_SIZE=1000\n";
+        $manualContent .= "    GRAPHQL_PERSISTED_QUERY_DIR=persisted-queries\n";
+        $manualContent .= "    GRAPHQL_PERSISTED_QUERY_MANIFEST=\n";
+        $manualContent .= "    GRAPHQL_PERSISTED_QUERY_STRICT=false\n";
+        $manualContent .= "    GRAPHQL_INTROSPECTION=true\n";
+        $manualContent .= "    GRAPHIQL_ENABLED=false\n";
         $manualContent .= "    ```\n\n"; // NOSONAR
+        $manualContent .= "    `DB_DRIVER` accepts `mysql`, `sqlite`, `postgres` and `sqlserver`. `DB_SCHEMA` (the search path) is only used by PostgreSQL. `DB_SSL_MODE` is the `sslmode` of PostgreSQL and the `encrypt` option of SQL Server.\n\n";
+        $manualContent .= "    `PASSWORD_HASH_ALGORITHM` accepts `argon2id` or `bcrypt`. Legacy `sha1(sha1(password))` hashes are still accepted and are replaced with the configured algorithm on the next successful login, so the `admin.password` column must be able to hold at least 100 characters.\n\n";
+        $manualContent .= "    When `REQUIRE_LOGIN=true`, the GraphQL endpoint rejects requests without a logged-in admin with HTTP 401 and an error whose `extensions.code` is `UNAUTHENTICATED`.\n\n";
+        $manualContent .= "    When `REQUIRE_PERMISSION=true`, every query and mutation is checked against the permissions granted to the admin level of the logged-in admin. Admins whose level is `SUPERUSER_LEVEL_ID` are granted every action. Permissions are managed on the *Admin Permissions* page and stored in the following table:\n\n";
+        $manualContent .= "    ```sql\n";
+        $manualContent .= "    CREATE TABLE admin_level_permission (\n";
+        $manualContent .= "        admin_level_permission_id VARCHAR(40) NOT NULL PRIMARY KEY,\n";
+        $manualContent .= "        admin_level_id VARCHAR(40) NOT NULL,\n";
+        $manualContent .= "        entity VARCHAR(100) NOT NULL,\n";
+        $manualContent .= "        allowed_list TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        allowed_detail TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        allowed_create TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        allowed_update TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        allowed_delete TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        allowed_toggle TINYINT(1) DEFAULT 0,\n";
+        $manualContent .= "        time_create TIMESTAMP NULL,\n";
+        $manualContent .= "        admin_create VARCHAR(40) NULL,\n";
+        $manualContent .= "        ip_create VARCHAR(50) NULL\n";
+        $manualContent .= "    );\n";
+        $manualContent .= "    ```\n\n";
         $manualContent .= "3.  Install dependencies:\n\n";
         $manualContent .= "    ```bash\n";
         $manualContent .= "    go mod init $moduleName\n";
@@ -1509,6 +2970,10 @@ GO;
         $manualContent .= "    go get github.com/google/uuid\n";
         $manualContent .= "    go get github.com/joho/godotenv\n";
         $manualContent .= "    go get github.com/gorilla/sessions\n";
+        $manualContent .= "    go get github.com/lib/pq\n";
+        $manualContent .= "    go get github.com/microsoft/go-mssqldb\n";
+        $manualContent .= "    go get golang.org/x/crypto\n";
+        $manualContent .= "    go get golang.org/x/net\n";
         $manualContent .= "    go mod tidy\n";
         $manualContent .= "    ```\n\n";
         $manualContent .= "4.  Run the application:\n\n";
@@ -1519,6 +2984,335 @@ GO;
 
         $manualContent .= $this->generateExample();
 
+        $manualContent .= "### Additional Filter Operators\r\n\r\n";
+        $manualContent .= "| Operator       | Description                                      | Example                                                |\r\n";
+        $manualContent .= "|----------------|--------------------------------------------------|--------------------------------------------------------|\r\n";
+        $manualContent .= "| `IS_NULL` / `IS_NOT_NULL` | Finds records where the field is (or is not) null. No value is needed. | `{field: \"deleted_at\", operator: IS_NULL}` |\r\n";
+        $manualContent .= "| `BETWEEN`      | Finds records where the field is between two values, inclusive. | `{field: \"price\", value: [10, 20], operator: BETWEEN}` |\r\n";
+        $manualContent .= "| `STARTS_WITH`  | Finds records where the text field starts with the value. | `{field: \"name\", value: \"Jo\", operator: STARTS_WITH}` |\r\n";
+        $manualContent .= "| `ENDS_WITH`    | Finds records where the text field ends with the value. | `{field: \"email\", value: \"@example.com\", operator: ENDS_WITH}` |\r\n";
+        $manualContent .= "| `NOT_CONTAINS` | Finds records where the text field does not contain the value. | `{field: \"title\", value: \"draft\", operator: NOT_CONTAINS}` |\r\n\r\n";
+        $manualContent .= "`IN` and `NOT_IN` take a list of values, e.g. `{field: \"category_id\", value: [\"1\", \"2\"], operator: IN}`, so values may contain commas. ";
+        $manualContent .= "Set `ignoreCase: true` to compare text case-insensitively with `EQUALS`, `NOT_EQUALS`, `CONTAINS`, `NOT_CONTAINS`, `STARTS_WITH` and `ENDS_WITH`. Fields that are not text, such as numbers and dates, are compared as text in these cases. ";
+        $manualContent .= "The wildcards `%` and `_` in the value are matched literally.\r\n\r\n";
+
+        $manualContent .= "### Filter Groups (`and`, `or`, `not`)\r\n\r\n";
+        $manualContent .= "A filter object may also group other filters. `and` and `or` take a list of filters, `not` takes a single filter. Groups can be nested to any depth, and the conditions of one filter object are combined with `AND`.\r\n\r\n";
+        $manualContent .= "**Example:** `status = A OR (status = B AND price > 10)`:\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "filter: [{or: [{field: \"status\", value: \"A\"}, {and: [{field: \"status\", value: \"B\"}, {field: \"price\", value: \"10\", operator: GREATER_THAN}]}]}]\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Cursor Pagination (Connections)\r\n\r\n";
+        $manualContent .= "Each list query has a Relay-style counterpart named `{entities}Connection`, e.g. `productsConnection`. ";
+        $manualContent .= "It takes `first`/`after` to page forward or `last`/`before` to page backward, together with the usual `orderBy` and `filter`. ";
+        $manualContent .= "Cursors are opaque and hold the values of the sort fields and the primary key, so pages stay stable while rows are inserted and large tables do not have to skip rows with an offset. ";
+        $manualContent .= "`totalCount` is only counted when it is requested. Sort connections by fields that are not null.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "query {\r\n";
+        $manualContent .= "  productsConnection(first: 20, after: \"<endCursor of the previous page>\", orderBy: [{field: \"name\"}]) {\r\n";
+        $manualContent .= "    edges { cursor node { name } }\r\n";
+        $manualContent .= "    pageInfo { hasNextPage endCursor }\r\n";
+        $manualContent .= "    totalCount\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Aggregates\r\n\r\n";
+        $manualContent .= "Each entity has an `{entity}Aggregate` query, e.g. `productAggregate`, that computes metrics over the records matching `filter`. ";
+        $manualContent .= "`groupBy` lists the fields to group by; each group is returned as a bucket, ordered by the group values. Without `groupBy`, a single bucket is returned.\r\n\r\n";
+        $manualContent .= "| Function | Field | Description |\r\n";
+        $manualContent .= "|----------|-------|-------------|\r\n";
+        $manualContent .= "| `COUNT`  | Optional | Counts the records, or the non-null values of the field. Used when no metric is given. |\r\n";
+        $manualContent .= "| `SUM` / `AVG` | Numeric fields only | Sum and average of the values. |\r\n";
+        $manualContent .= "| `MIN` / `MAX` | Any field | Smallest and largest value. |\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "query {\r\n";
+        $manualContent .= "  productAggregate(filter: [{field: \"active\", value: true}], groupBy: [\"category_id\"], metrics: [{fn: COUNT}, {fn: SUM, field: \"price\"}]) {\r\n";
+        $manualContent .= "    group { field value }\r\n";
+        $manualContent .= "    metrics { fn field value }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Bulk Mutations\r\n\r\n";
+        $manualContent .= "Each entity has bulk mutations that run in a single database transaction, so either all records are changed or none is:\r\n\r\n";
+        $manualContent .= "- `create{Entities}(inputs: [...])` creates several records, e.g. `createProducts`.\r\n";
+        $manualContent .= "- `update{Entities}(filter: [...], input: {...})` applies the same input to every record matching the filter.\r\n";
+        $manualContent .= "- `delete{Entities}(filter: [...])` deletes every record matching the filter.\r\n\r\n";
+        $manualContent .= "They return the number of affected records and their IDs. ";
+        $manualContent .= "`update{Entities}` and `delete{Entities}` first select the records matching the filter, then change exactly these records, so `ids` lists the records that were changed even if other records start to match the filter meanwhile. ";
+        $manualContent .= "`affected` is the number of records the database reports as changed; MySQL does not count the records whose values were already those of the input.\r\n\r\n";
+        $manualContent .= "To prevent accidental changes to a whole table, `update{Entities}` and `delete{Entities}` refuse an empty filter unless `force: true` is passed.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "mutation {\r\n";
+        $manualContent .= "  updateProducts(filter: [{field: \"category_id\", value: \"1\"}], input: {active: false}) {\r\n";
+        $manualContent .= "    affected\r\n";
+        $manualContent .= "    ids\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Transactions\r\n\r\n";
+        $manualContent .= "Send a request with the header `X-Transaction: true` to run all of its mutations in a single database transaction. ";
+        $manualContent .= "The transaction is committed if every field succeeds and rolled back if any field returns an error, so the mutations of the request are applied together or not at all. ";
+        $manualContent .= "The fields of such a request are resolved one at a time, and bulk mutations join its transaction instead of starting their own.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "mutation {\r\n";
+        $manualContent .= "  category: createCategory(input: {name: \"Drinks\"}) { category_id }\r\n";
+        $manualContent .= "  deleted: deleteProducts(filter: [{field: \"active\", value: \"false\"}]) { affected }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $auditSources = [
+            'timeCreate' => 'the time the record is created',
+            'adminCreate' => 'the ID of the admin who creates the record',
+            'ipCreate' => 'the IP address of the client that creates the record',
+            'timeEdit' => 'the time the record is last changed',
+            'adminEdit' => 'the ID of the admin who last changes the record',
+            'ipEdit' => 'the IP address of the client that last changes the record'
+        ];
+        $auditLines = "";
+        foreach($auditSources as $key => $source)
+        {
+            if(isset($this->backendHandledColumns[$key]))
+            {
+                $auditLines .= "- `{$this->backendHandledColumns[$key]['columnName']}`: $source.\r\n";
+            }
+        }
+        if(!empty($auditLines))
+        {
+            $manualContent .= "### Audit Columns\r\n\r\n";
+            $manualContent .= "The following columns are filled by the server on every create, update, toggle and bulk mutation. ";
+            $manualContent .= "They are not part of the input types, so clients cannot set them:\r\n\r\n";
+            $manualContent .= $auditLines . "\r\n";
+        }
+
+        $manualContent .= "### Audit Trail\r\n\r\n";
+        $manualContent .= "Every create, update, delete and toggle mutation, including the bulk mutations, and every change made on the *Admin* page writes an entry to the `audit_log` table. ";
+        $manualContent .= "An entry holds the entity, the primary key of the record, the action, the changed fields before and after the change as JSON, the admin, the IP address and the time. ";
+        $manualContent .= "Password values are masked. The entry is written in the transaction of the change, so both are committed or neither is: if the entry cannot be written, e.g. because the table does not exist, the change is rolled back and the mutation fails.\r\n\r\n";
+        $manualContent .= "```sql\r\n";
+        $manualContent .= "CREATE TABLE audit_log (\r\n";
+        $manualContent .= "    audit_log_id VARCHAR(40) NOT NULL PRIMARY KEY,\r\n";
+        $manualContent .= "    entity VARCHAR(100) NOT NULL,\r\n";
+        $manualContent .= "    record_id VARCHAR(100) NOT NULL,\r\n";
+        $manualContent .= "    action VARCHAR(20) NOT NULL,\r\n";
+        $manualContent .= "    before_data TEXT NULL,\r\n";
+        $manualContent .= "    after_data TEXT NULL,\r\n";
+        $manualContent .= "    admin_id VARCHAR(40) NULL,\r\n";
+        $manualContent .= "    ip_address VARCHAR(50) NULL,\r\n";
+        $manualContent .= "    time_create TIMESTAMP NULL\r\n";
+        $manualContent .= ");\r\n";
+        $manualContent .= "CREATE INDEX audit_log_record ON audit_log (entity, record_id);\r\n";
+        $manualContent .= "```\r\n\r\n";
+        $manualContent .= "`auditLogs` lists the entries of all entities and requires the list permission on `audit_log`. ";
+        $manualContent .= "`{entity}History(id: ...)` lists the entries of a single record, most recent first, and requires the detail permission on the entity.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "query {\r\n";
+        $manualContent .= "  productHistory(id: \"1\", limit: 20) {\r\n";
+        $manualContent .= "    items { action before_data after_data admin_id ip_address time_create }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "  auditLogs(filter: [{field: \"admin_id\", value: \"1\"}], orderBy: [{field: \"time_create\", direction: DESC}]) {\r\n";
+        $manualContent .= "    items { entity record_id action time_create }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Input Validation\r\n\r\n";
+        $manualContent .= "The fields of the input types are checked before any SQL is run. The rules are derived from the columns: ";
+        $manualContent .= "NOT NULL columns without a default value are required when creating a record, CHAR and VARCHAR values may not be longer than the column, ";
+        $manualContent .= "TINYINT, SMALLINT and UNSIGNED values must be within the range of the column, and columns named `email`, `*_email`, `url`, `*_url` or `website` must hold a valid email address or http(s) URL. ";
+        $manualContent .= "A column of the schema may set its own rules with a `validation` object, e.g. `\"validation\": {\"minLength\": 3, \"pattern\": \"^[A-Z]\", \"min\": 0, \"max\": 100, \"format\": \"email\", \"required\": true}`. ";
+        $manualContent .= "Updates only check the fields that are set.\r\n\r\n";
+        $manualContent .= "Invalid input is refused with an error whose `extensions.code` is `VALIDATION` and whose `extensions.fields` lists every invalid field, with the rule that failed and a translated message. ";
+        $manualContent .= "For bulk creates, the field is prefixed with the position of the input, e.g. `inputs.2.name`.\r\n\r\n";
+        $manualContent .= "```json\r\n";
+        $manualContent .= "{\r\n";
+        $manualContent .= "  \"errors\": [{\r\n";
+        $manualContent .= "    \"message\": \"Invalid input: name is required\",\r\n";
+        $manualContent .= "    \"path\": [\"createProduct\"],\r\n";
+        $manualContent .= "    \"extensions\": {\r\n";
+        $manualContent .= "      \"code\": \"VALIDATION\",\r\n";
+        $manualContent .= "      \"fields\": [{\"field\": \"name\", \"rule\": \"required\", \"message\": \"name is required\"}]\r\n";
+        $manualContent .= "    }\r\n";
+        $manualContent .= "  }]\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $versionLines = "";
+        foreach ($this->analyzedSchema as $tableName => $tableInfo) {
+            $versionColumn = $this->getVersionColumn($tableInfo);
+            if ($versionColumn !== null) {
+                $versionLines .= "- `{$tableName}`: `{$versionColumn}`\r\n";
+            }
+        }
+        $manualContent .= "### Optimistic Concurrency\r\n\r\n";
+        $manualContent .= "Every type has a `rowVersion` field that holds the current version of the record. ";
+        $manualContent .= "A `version` column is used when the table has one; it starts at 1 and is incremented by every change. Otherwise the time of the last change is used. ";
+        $manualContent .= "Pass the version read with the record as `expectedVersion` to `update{Entity}`: if the record was changed since, it is not updated and the error has `extensions.code` set to `CONFLICT` and `extensions.currentVersion` set to the current version. ";
+        $manualContent .= "Without `expectedVersion`, the update always overwrites the record. The form of the *Admin* page does the same check with the `time_edit` column of `admin`.\r\n\r\n";
+        $manualContent .= "A time only changes once per second, so two changes made within the same second cannot be told apart. Add a `version` column (an integer) to the tables that need a strict check.\r\n\r\n";
+        $manualContent .= (empty($versionLines) ? "No table of this application has a version column, so `rowVersion` is always null." : "The version columns of this application are:\r\n\r\n" . $versionLines) . "\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "mutation {\r\n";
+        $manualContent .= "  updateProduct(id: \"1\", input: {name: \"Tea\"}, expectedVersion: \"2024-05-01 10:15:00\") { product_id name rowVersion }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Subscriptions\r\n\r\n";
+        $manualContent .= "Clients can be notified of the changes made by other users instead of polling. Each entity has a `{entity}Changed(filter: ...)` subscription, ";
+        $manualContent .= "which sends a `{Entity}Change` with the `action` (`CREATED`, `UPDATED` or `DELETED`), the `id` and the record every time a record matching the filter is created, updated, toggled, restored or deleted. ";
+        $manualContent .= "Deleted records are sent with their values before the deletion. A change is only sent once it is committed; the changes of a request sent with `X-Transaction: true` are sent when the whole request is committed.\r\n\r\n";
+        $manualContent .= "Subscriptions use the `graphql-transport-ws` protocol over a WebSocket connection to the GraphQL endpoint, e.g. `ws://localhost:8080/graphql`, as supported by the `graphql-ws` client library. ";
+        $manualContent .= "The connection is authenticated with the session cookie of the logged-in admin, who needs the list permission on the entity, and browsers may only open it from the origin of the application.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "subscription {\r\n";
+        $manualContent .= "  productChanged(filter: [{field: \"active\", value: true}]) {\r\n";
+        $manualContent .= "    action\r\n";
+        $manualContent .= "    id\r\n";
+        $manualContent .= "    product { name price }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+        $manualContent .= "The filters are the same as those of the list queries, but they are evaluated by the application: text is compared case-sensitively unless `ignoreCase` is set, except by `CONTAINS`. ";
+        $manualContent .= "The changes are distributed within the running process, so when several instances of the application run behind a load balancer, a client is only notified of the changes made through the instance it is connected to.\r\n\r\n";
+
+        $manualContent .= "### Query Limits\r\n\r\n";
+        $manualContent .= "Relation fields can be nested without end (`product { category { products { items { category ... } } } }`), so every request is checked before it runs:\r\n\r\n";
+        $manualContent .= "- Its depth, the number of nested levels of fields, must not exceed `GRAPHQL_MAX_DEPTH` (10 by default).\r\n";
+        $manualContent .= "- Its cost must not exceed `GRAPHQL_MAX_COST` (10000 by default). Each field that has a selection set costs one; fields without one are free. ";
+        $manualContent .= "The cost of a list query or a connection, including the cost of its selection set, is multiplied by its `limit`, `first` or `last`, or by 10 when none is given.\r\n";
+        $manualContent .= "- A `limit`, `first` or `last` must not exceed `GRAPHQL_MAX_PAGE_SIZE` (100 by default). A `limit` or a `page` below 1, or a negative `offset`, `first` or `last`, is rejected.\r\n\r\n";
+        $manualContent .= "For example, `{ products(limit: 50) { items { name category { name } } } }` costs 50 × (1 + 1 + 1) = 150. ";
+        $manualContent .= "A request that exceeds a limit is rejected with a `VALIDATION` error before any field is resolved; its `extensions` contain the computed value and the limit, ";
+        $manualContent .= "e.g. `cost` and `maxCost`. A request whose depth and cost cannot be computed, because it cannot be parsed or its operation cannot be found, is rejected with a `VALIDATION` error too. ";
+        $manualContent .= "The depth and the cost of every request are written to the server log with its correlation id. Setting a limit to `0` disables it.\r\n\r\n";
+
+        $manualContent .= "### Persisted Queries\r\n\r\n";
+        $manualContent .= "Clients can send the SHA-256 hash of a query instead of the query, as Apollo clients do with automatic persisted queries (APQ):\r\n\r\n";
+        $manualContent .= "```json\r\n";
+        $manualContent .= "{\"extensions\": {\"persistedQuery\": {\"version\": 1, \"sha256Hash\": \"1c7e1e34...\"}}, \"variables\": {}}\r\n";
+        $manualContent .= "```\r\n\r\n";
+        $manualContent .= "When the hash is unknown, the request fails with the message `PersistedQueryNotFound` and the code `PERSISTED_QUERY_NOT_FOUND`. ";
+        $manualContent .= "The client then sends the query together with its hash; the query is registered and runs, and later requests only need the hash. ";
+        $manualContent .= "A query whose hash does not match is rejected with a `VALIDATION` error. The same applies to the `subscribe` messages of WebSocket connections.\r\n\r\n";
+        $manualContent .= "`GRAPHQL_PERSISTED_QUERY_STORE` selects where the registered queries are kept:\r\n\r\n";
+        $manualContent .= "- `memory` (the default): the `GRAPHQL_PERSISTED_QUERY_CACHE_SIZE` most recently used queries are kept in memory, per instance.\r\n";
+        $manualContent .= "- `file`: each query is written to a file named after its hash in `GRAPHQL_PERSISTED_QUERY_DIR`.\r\n";
+        $manualContent .= "- `sql`: the queries are kept in the `persisted_query` table, shared by every instance.\r\n";
+        $manualContent .= "- `none`: queries cannot be registered, and requests sent by hash fail with `PERSISTED_QUERY_NOT_SUPPORTED` unless the query is in the manifest.\r\n\r\n";
+        $manualContent .= "```sql\r\n";
+        $manualContent .= "CREATE TABLE persisted_query (\r\n";
+        $manualContent .= "    hash CHAR(64) NOT NULL PRIMARY KEY,\r\n";
+        $manualContent .= "    query TEXT NOT NULL,\r\n";
+        $manualContent .= "    time_create TIMESTAMP NULL\r\n";
+        $manualContent .= ");\r\n";
+        $manualContent .= "```\r\n\r\n";
+        $manualContent .= "`GRAPHQL_PERSISTED_QUERY_MANIFEST` is the path of a JSON manifest of operations that are always known, loaded at startup. ";
+        $manualContent .= "It is either a manifest in the format generated by Apollo, `{\"format\": \"apollo-persisted-query-manifest\", \"version\": 1, \"operations\": [{\"id\": \"<sha256>\", \"name\": \"...\", \"type\": \"query\", \"body\": \"...\"}]}`, ";
+        $manualContent .= "or an object that maps the hashes to the queries. Every hash must be the SHA-256 hash of its query, otherwise the application does not start.\r\n\r\n";
+        $manualContent .= "When `GRAPHQL_PERSISTED_QUERY_STRICT=true`, which requires a manifest, only the operations of the manifest may run, whether they are sent by hash or in full, ";
+        $manualContent .= "and clients cannot register new queries. Any other request is rejected with a `FORBIDDEN` error. Use it in production once the manifest lists every operation of the clients.\r\n\r\n";
+
+        $manualContent .= "### GraphQL Explorer and Introspection\r\n\r\n";
+        $manualContent .= "When `GRAPHIQL_ENABLED=true`, an interactive explorer is served at `/graphiql`, e.g. `http://localhost:8080/graphiql`. ";
+        $manualContent .= "Its page, scripts and styles are embedded in the application binary, so it works without access to a CDN. ";
+        $manualContent .= "Only logged-in admins may open it: log in to the application first, and the explorer sends its requests with the same session. ";
+        $manualContent .= "It runs queries and mutations (optionally with `X-Transaction: true`), runs subscriptions over WebSocket, and browses the types of the schema.\r\n\r\n";
+        $manualContent .= "When `GRAPHQL_INTROSPECTION=false`, the `__schema` and `__type` fields of introspection queries return nothing, so clients cannot discover the schema. ";
+        $manualContent .= "Logged-in admins whose level is `SUPERUSER_LEVEL_ID` can still introspect it, e.g. to browse it in the explorer. `__typename` is always available. ";
+        $manualContent .= "Keep in mind that in strict persisted query mode, the explorer can only run the operations of the manifest.\r\n\r\n";
+
+        $manualContent .= "### Scalars\r\n\r\n";
+        $manualContent .= "Besides the built-in scalars, the schema declares these custom scalars for the columns of the tables:\r\n\r\n";
+        $manualContent .= "| Scalar     | Columns                   | Format                                                                 |\r\n";
+        $manualContent .= "|------------|---------------------------|------------------------------------------------------------------------|\r\n";
+        $manualContent .= "| `Date`     | `DATE`                    | `\"2024-05-17\"`                                                         |\r\n";
+        $manualContent .= "| `DateTime` | `DATETIME`, `TIMESTAMP`   | RFC 3339, e.g. `\"2024-05-17T08:30:00+07:00\"`                           |\r\n";
+        $manualContent .= "| `Decimal`  | `DECIMAL`, `NUMERIC`      | A string such as `\"12.50\"`; a number is also accepted as input          |\r\n";
+        $manualContent .= "| `JSON`     | `JSON`, `JSONB`           | Any JSON value, sent as is rather than as a string                     |\r\n";
+        $manualContent .= "| `Any`      | The `value` of filters    | Any value; numbers, booleans, null and lists keep their type           |\r\n\r\n";
+        $manualContent .= "Times are stored without a time zone, in the time zone of the server, which is set with the `TZ` environment variable (e.g. `TZ=Asia/Jakarta`). ";
+        $manualContent .= "A `DateTime` is returned with the offset of that time zone. A `DateTime` sent with another offset, e.g. `\"2024-05-17T01:30:00Z\"`, is converted to it before it is stored, ";
+        $manualContent .= "and one sent without an offset, e.g. `\"2024-05-17 08:30:00\"`, is taken as a time of that time zone.\r\n\r\n";
+        $manualContent .= "A `Decimal` keeps every digit of the column, which a `Float` would round. Send it as a string to keep more than 15 significant digits, ";
+        $manualContent .= "and convert it with a decimal library on the client rather than to a floating-point number. `FLOAT` and `DOUBLE` columns remain `Float`.\r\n\r\n";
+        $manualContent .= "A value that does not have the format of its scalar, e.g. `\"2024-13-01\"` for a `Date`, is rejected before any field is resolved.\r\n\r\n";
+
+        $manualContent .= "### Errors\r\n\r\n";
+        $manualContent .= "Every error returned by a resolver has a code in `extensions.code`:\r\n\r\n";
+        $manualContent .= "- `NOT_FOUND`: the record does not exist, e.g. when deleting or restoring it.\r\n";
+        $manualContent .= "- `VALIDATION`: the arguments are not valid, e.g. an invalid filter field or input value.\r\n";
+        $manualContent .= "- `FORBIDDEN`: the admin is not allowed to perform the action.\r\n";
+        $manualContent .= "- `CONFLICT`: the record was changed since it was read.\r\n";
+        $manualContent .= "- `INTERNAL`: the request failed for a reason the client cannot fix, such as a database error.\r\n\r\n";
+        $manualContent .= "The message of an `INTERNAL` error does not contain the cause of the error. The cause is written to the server log together with a correlation id, ";
+        $manualContent .= "which is returned in `extensions.correlationId` and in the `X-Correlation-Id` response header. ";
+        $manualContent .= "A request that already has an `X-Correlation-Id` header, e.g. from a proxy, keeps its id.\r\n\r\n";
+        $manualContent .= "```json\r\n";
+        $manualContent .= "{\r\n";
+        $manualContent .= "  \"errors\": [{\r\n";
+        $manualContent .= "    \"message\": \"Failed to create product: see the server log, reference 1b4e28ba-2fa1-4d2b-883f-0016d3cca427\",\r\n";
+        $manualContent .= "    \"path\": [\"createProduct\"],\r\n";
+        $manualContent .= "    \"extensions\": {\"code\": \"INTERNAL\", \"correlationId\": \"1b4e28ba-2fa1-4d2b-883f-0016d3cca427\"}\r\n";
+        $manualContent .= "  }]\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $trashEntities = [];
+        foreach ($this->analyzedSchema as $tableName => $tableInfo) {
+            if ($this->isTrashEnabled($tableName)) {
+                $trashEntities[] = "`{$tableName}`";
+            }
+        }
+        $manualContent .= "### Trash\r\n\r\n";
+        $manualContent .= "A table uses the trash when the schema also defines a `{table}_trash` entity, or when its entity sets `trash` to `true`. ";
+        $manualContent .= "The delete mutations of such a table copy each record to the `trash` table as JSON before deleting it, in the same transaction, so the default list queries no longer return it. ";
+        $manualContent .= (empty($trashEntities) ? "No table of this application uses the trash." : "The following tables use the trash: " . implode(", ", $trashEntities) . ".") . "\r\n\r\n";
+        $manualContent .= "```sql\r\n";
+        $manualContent .= "CREATE TABLE trash (\r\n";
+        $manualContent .= "    trash_id VARCHAR(40) NOT NULL PRIMARY KEY,\r\n";
+        $manualContent .= "    entity VARCHAR(100) NOT NULL,\r\n";
+        $manualContent .= "    record_id VARCHAR(100) NOT NULL,\r\n";
+        $manualContent .= "    data TEXT NULL,\r\n";
+        $manualContent .= "    admin_delete VARCHAR(40) NULL,\r\n";
+        $manualContent .= "    ip_delete VARCHAR(50) NULL,\r\n";
+        $manualContent .= "    time_delete TIMESTAMP NULL\r\n";
+        $manualContent .= ");\r\n";
+        $manualContent .= "CREATE INDEX trash_record ON trash (entity, record_id);\r\n";
+        $manualContent .= "```\r\n\r\n";
+        $manualContent .= "`trash{Entities}` lists the deleted records of an entity and requires the list permission. ";
+        $manualContent .= "`restore{Entity}(id: ...)` inserts the most recently deleted version of a record back into its table, and `purge{Entity}(id: ...)` removes its deleted versions for good. Both require the delete permission and are written to the audit trail.\r\n\r\n";
+        $manualContent .= "When `TRASH_REQUIRED=true`, admins deleted on the *Admin* page are also moved to the trash.\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "query {\r\n";
+        $manualContent .= "  trashProducts(limit: 20) {\r\n";
+        $manualContent .= "    items { record_id data admin_delete time_delete }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n\r\n";
+        $manualContent .= "mutation {\r\n";
+        $manualContent .= "  restoreProduct(id: \"1\") { product_id name }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
+        $manualContent .= "### Related Records\r\n\r\n";
+        $manualContent .= "Fields that resolve a foreign key (e.g. the category of each product in a list) are loaded in batches. ";
+        $manualContent .= "The lookups made while resolving one request are collected into a single `WHERE id IN (...)` query per table, and each record is read only once per request.\r\n\r\n";
+        $manualContent .= "A type that is referenced by a foreign key also lists the records that refer to it, e.g. `products` on `Category` for the `category_id` column of `product`. ";
+        $manualContent .= "These fields take the same `limit`, `offset`, `page`, `orderBy` and `filter` arguments as the list query and return a page. ";
+        $manualContent .= "The pages of all parents in a list are fetched together with one query, which numbers the rows with `ROW_NUMBER()` (MySQL 8.0, MariaDB 10.2 and SQLite 3.25 or newer).\r\n\r\n";
+        $manualContent .= "```graphql\r\n";
+        $manualContent .= "query {\r\n";
+        $manualContent .= "  categories(limit: 10) {\r\n";
+        $manualContent .= "    items {\r\n";
+        $manualContent .= "      name\r\n";
+        $manualContent .= "      products(limit: 5, orderBy: [{field: \"name\"}]) { total items { name } }\r\n";
+        $manualContent .= "    }\r\n";
+        $manualContent .= "  }\r\n";
+        $manualContent .= "}\r\n";
+        $manualContent .= "```\r\n\r\n";
+
         return $manualContent;
     }
 }
\ No newline at end of file
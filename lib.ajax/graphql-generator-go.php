@@ -24,6 +24,8 @@ function setDatabaseConfiguration($application, $envTemplate)
             $driver = $databaseConfig->getDriver();
             if($driver == 'pgsql') {
                 $driver = 'postgres';
+            } else if($driver == 'sqlsrv') {
+                $driver = 'sqlserver';
             }
 
             $envTemplate = str_replace('${DB_DRIVER}', $driver, $envTemplate);
@@ -33,6 +35,7 @@ function setDatabaseConfiguration($application, $envTemplate)
             $envTemplate = str_replace('${DB_FILE}', $databaseConfig->getDatabaseFilePath(), $envTemplate);
             $envTemplate = str_replace('${DB_USER}', $databaseConfig->getUsername(), $envTemplate);
             $envTemplate = str_replace('${DB_PASS}', $databaseConfig->getPassword(), $envTemplate);
+            $envTemplate = str_replace('${DB_SCHEMA}', $driver == 'postgres' ? $databaseConfig->getDatabaseSchema() : '', $envTemplate);
         }
     }
     return $envTemplate;
@@ -88,13 +91,29 @@ DB_NAME=\${DB_NAME}
 DB_FILE=\${DB_FILE}
 DB_USER=\${DB_USER}
 DB_PASS=\${DB_PASS}
+DB_SCHEMA=\${DB_SCHEMA}
+DB_SSL_MODE=disable
 
 SERVER_PORT=8080
 SESSION_SECRET=a-very-secret-key-that-you-should-change
 REQUIRE_LOGIN=true
+PASSWORD_HASH_ALGORITHM=argon2id
+REQUIRE_PERMISSION=false
+SUPERUSER_LEVEL_ID=superuser
+TRASH_REQUIRED=false
 
 GRAPHQL_ENDPOINT=/graphql
 GRAPHQL_SCHEMA=schema/schema.graphqls
+GRAPHQL_MAX_DEPTH=10
+GRAPHQL_MAX_COST=10000
+GRAPHQL_MAX_PAGE_SIZE=100
+GRAPHQL_PERSISTED_QUERY_STORE=memory
+GRAPHQL_PERSISTED_QUERY_CACHE_SIZE=1000
+GRAPHQL_PERSISTED_QUERY_DIR=persisted-queries
+GRAPHQL_PERSISTED_QUERY_MANIFEST=
+GRAPHQL_PERSISTED_QUERY_STRICT=false
+GRAPHQL_INTROSPECTION=true
+GRAPHIQL_ENABLED=false
 THEME_CACHE_TIME=86400
 
 DEFAULT_LANGUAGE=en